@@ -0,0 +1,519 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"cmp"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/54b3r/platform-operator-blueprint/api/v1beta1"
+)
+
+// InitContainersListAnnotation marks a hub WebApp whose init containers were all given in
+// v1alpha1's InitContainers list. Without it, the first v1beta1 init container is served
+// as v1alpha1's single InitContainer.
+const InitContainersListAnnotation = "app.54b3r.io/init-containers-list"
+
+// PortsListAnnotation marks a hub WebApp whose single default port was given in
+// v1alpha1's Ports list. Without it, such a port is served as v1alpha1's Port alone.
+const PortsListAnnotation = "app.54b3r.io/ports-list"
+
+// DefaultPortName is the name given to the v1alpha1 Port in v1beta1, and on the Service.
+const DefaultPortName = "http"
+
+// DefaultMountPath is where the storage volume is mounted in the main container.
+const DefaultMountPath = "/data"
+
+var _ conversion.Convertible = &WebApp{}
+
+// ConvertTo converts this WebApp (v1alpha1) to the hub version (v1beta1).
+func (src *WebApp) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.WebApp)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 WebApp but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	convertSpecToHub(&src.Spec, &dst.Spec)
+	convertStatusToHub(&src.Status, &dst.Status)
+
+	delete(dst.Annotations, InitContainersListAnnotation)
+	if src.Spec.InitContainer == nil && len(src.Spec.InitContainers) > 0 {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[InitContainersListAnnotation] = "true"
+	}
+	delete(dst.Annotations, PortsListAnnotation)
+	if len(src.Spec.Ports) > 0 && isPortShape(dst.Spec.Ports) {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[PortsListAnnotation] = "true"
+	}
+
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	return nil
+}
+
+// ConvertFrom converts from the hub version (v1beta1) to this version (v1alpha1).
+func (dst *WebApp) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.WebApp)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 WebApp but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	_, initContainersAsList := dst.Annotations[InitContainersListAnnotation]
+	_, portsAsList := dst.Annotations[PortsListAnnotation]
+	delete(dst.Annotations, InitContainersListAnnotation)
+	delete(dst.Annotations, PortsListAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	convertSpecFromHub(&src.Spec, &dst.Spec, initContainersAsList, portsAsList)
+	convertStatusFromHub(&src.Status, &dst.Status)
+	return nil
+}
+
+// convertSpecToHub maps every v1alpha1 spec field onto its v1beta1 equivalent. It works
+// on a deep copy of src, so fields of the same type are moved over as they are and hub and
+// spoke never share memory; types that differ only in their package are converted.
+func convertSpecToHub(src *WebAppSpec, dst *v1beta1.WebAppSpec) {
+	src = src.DeepCopy()
+	dst.Image = src.Image
+	dst.Replicas = src.Replicas
+	dst.Resources = src.Resources
+	dst.Env = src.Env
+	dst.EnvFrom = src.EnvFrom
+	dst.LivenessProbe = src.LivenessProbe
+	dst.ReadinessProbe = src.ReadinessProbe
+	dst.StartupProbe = src.StartupProbe
+	dst.HealthCheck = (*v1beta1.HealthCheckSpec)(src.HealthCheck)
+	dst.Autoscaling = (*v1beta1.AutoscalingSpec)(src.Autoscaling)
+
+	dst.Schedules = nil
+	if src.Schedules != nil {
+		dst.Schedules = make([]v1beta1.ReplicaSchedule, len(src.Schedules))
+		for i := range src.Schedules {
+			dst.Schedules[i] = v1beta1.ReplicaSchedule(src.Schedules[i])
+		}
+	}
+
+	dst.Expose = convertExposeToHub(src.Expose)
+	dst.Service = nil
+	if src.Service != nil {
+		dst.Service = &v1beta1.ServiceSpec{
+			Type:                  v1beta1.ServiceType(src.Service.Type),
+			Annotations:           src.Service.Annotations,
+			ExternalTrafficPolicy: src.Service.ExternalTrafficPolicy,
+		}
+	}
+	dst.ImagePolicy = v1beta1.ImagePolicy(src.ImagePolicy)
+	dst.ImagePullSecrets = src.ImagePullSecrets
+	dst.DriftPolicy = v1beta1.DriftPolicy(src.DriftPolicy)
+	dst.Rollout = nil
+	if src.Rollout != nil {
+		dst.Rollout = &v1beta1.RolloutSpec{
+			Strategy:     v1beta1.RolloutStrategy(src.Rollout.Strategy),
+			PreviewPause: src.Rollout.PreviewPause,
+		}
+		if src.Rollout.Steps != nil {
+			dst.Rollout.Steps = make([]v1beta1.CanaryStep, len(src.Rollout.Steps))
+			for i := range src.Rollout.Steps {
+				dst.Rollout.Steps[i] = v1beta1.CanaryStep(src.Rollout.Steps[i])
+			}
+		}
+	}
+	dst.Suspend = (*v1beta1.SuspendSpec)(src.Suspend)
+	dst.Disruption = (*v1beta1.DisruptionSpec)(src.Disruption)
+
+	dst.Ports = nil
+	switch {
+	case len(src.Ports) > 0:
+		dst.Ports = make([]v1beta1.PortSpec, len(src.Ports))
+		for i, p := range src.Ports {
+			dst.Ports[i] = v1beta1.PortSpec{
+				Name:          p.Name,
+				ContainerPort: p.ContainerPort,
+				ServicePort:   p.ServicePort,
+				Protocol:      p.Protocol,
+				AppProtocol:   (*v1beta1.AppProtocol)(p.AppProtocol),
+			}
+		}
+	case src.Port != 0:
+		dst.Ports = []v1beta1.PortSpec{{
+			Name:          DefaultPortName,
+			ContainerPort: src.Port,
+			Protocol:      corev1.ProtocolTCP,
+		}}
+	}
+
+	dst.Storage = (*v1beta1.StorageSpec)(src.Storage)
+	if dst.Storage != nil {
+		dst.Storage.MountPath = cmp.Or(dst.Storage.MountPath, DefaultMountPath)
+	}
+	dst.Volumes = nil
+	if src.Volumes != nil {
+		dst.Volumes = make([]v1beta1.VolumeSpec, len(src.Volumes))
+		for i := range src.Volumes {
+			dst.Volumes[i] = v1beta1.VolumeSpec(src.Volumes[i])
+		}
+	}
+	dst.ScratchVolumes = nil
+	if src.ScratchVolumes != nil {
+		dst.ScratchVolumes = make([]v1beta1.ScratchVolumeSpec, len(src.ScratchVolumes))
+		for i := range src.ScratchVolumes {
+			dst.ScratchVolumes[i] = v1beta1.ScratchVolumeSpec(src.ScratchVolumes[i])
+		}
+	}
+
+	dst.InitContainers = nil
+	if src.InitContainer != nil {
+		// The hub requires a name; an unset v1alpha1 name means the default.
+		dst.InitContainers = []v1beta1.InitContainerSpec{v1beta1.InitContainerSpec(*src.InitContainer)}
+		dst.InitContainers[0].Name = cmp.Or(src.InitContainer.Name, DefaultInitContainerName)
+	}
+	for _, c := range src.InitContainers {
+		dst.InitContainers = append(dst.InitContainers, v1beta1.InitContainerSpec{
+			Name:          c.Name,
+			Image:         c.Image,
+			Command:       c.Command,
+			Args:          c.Args,
+			Env:           c.Env,
+			EnvFrom:       c.EnvFrom,
+			Ports:         c.Ports,
+			RestartPolicy: c.RestartPolicy,
+			Resources:     c.Resources,
+			VolumeMounts:  c.VolumeMounts,
+		})
+	}
+	dst.Sidecars = nil
+	for _, c := range src.Sidecars {
+		dst.Sidecars = append(dst.Sidecars, v1beta1.ContainerSpec{
+			Name:           c.Name,
+			Image:          c.Image,
+			Command:        c.Command,
+			Args:           c.Args,
+			Env:            c.Env,
+			EnvFrom:        c.EnvFrom,
+			Ports:          c.Ports,
+			Resources:      c.Resources,
+			VolumeMounts:   c.VolumeMounts,
+			LivenessProbe:  c.LivenessProbe,
+			ReadinessProbe: c.ReadinessProbe,
+			StartupProbe:   c.StartupProbe,
+		})
+	}
+
+	dst.NodeSelector = src.NodeSelector
+	dst.Tolerations = src.Tolerations
+	dst.Affinity = src.Affinity
+	dst.TopologySpreadConstraints = src.TopologySpreadConstraints
+	dst.Spread = v1beta1.SpreadPreset(src.Spread)
+	dst.PriorityClassName = src.PriorityClassName
+	dst.SecurityContext = nil
+	if src.SecurityContext != nil {
+		dst.SecurityContext = &v1beta1.SecurityContextSpec{
+			Profile:                v1beta1.SecurityProfile(src.SecurityContext.Profile),
+			RunAsUser:              src.SecurityContext.RunAsUser,
+			RunAsGroup:             src.SecurityContext.RunAsGroup,
+			FSGroup:                src.SecurityContext.FSGroup,
+			ReadOnlyRootFilesystem: src.SecurityContext.ReadOnlyRootFilesystem,
+			AddCapabilities:        src.SecurityContext.AddCapabilities,
+		}
+	}
+}
+
+// convertSpecFromHub maps every v1beta1 spec field onto its v1alpha1 equivalent, working on
+// a deep copy of src like convertSpecToHub. The first init container becomes InitContainer
+// unless initContainersAsList is set, and the others go to InitContainers. A single
+// default port is served as Port alone unless portsAsList is set.
+func convertSpecFromHub(src *v1beta1.WebAppSpec, dst *WebAppSpec, initContainersAsList, portsAsList bool) {
+	src = src.DeepCopy()
+	dst.Image = src.Image
+	dst.Replicas = src.Replicas
+	dst.Resources = src.Resources
+	dst.Env = src.Env
+	dst.EnvFrom = src.EnvFrom
+	dst.LivenessProbe = src.LivenessProbe
+	dst.ReadinessProbe = src.ReadinessProbe
+	dst.StartupProbe = src.StartupProbe
+	dst.HealthCheck = (*HealthCheckSpec)(src.HealthCheck)
+	dst.Autoscaling = (*AutoscalingSpec)(src.Autoscaling)
+
+	dst.Schedules = nil
+	if src.Schedules != nil {
+		dst.Schedules = make([]ReplicaSchedule, len(src.Schedules))
+		for i := range src.Schedules {
+			dst.Schedules[i] = ReplicaSchedule(src.Schedules[i])
+		}
+	}
+
+	dst.Expose = convertExposeFromHub(src.Expose)
+	dst.Service = nil
+	if src.Service != nil {
+		dst.Service = &ServiceSpec{
+			Type:                  ServiceType(src.Service.Type),
+			Annotations:           src.Service.Annotations,
+			ExternalTrafficPolicy: src.Service.ExternalTrafficPolicy,
+		}
+	}
+	dst.ImagePolicy = ImagePolicy(src.ImagePolicy)
+	dst.ImagePullSecrets = src.ImagePullSecrets
+	dst.DriftPolicy = DriftPolicy(src.DriftPolicy)
+	dst.Rollout = nil
+	if src.Rollout != nil {
+		dst.Rollout = &RolloutSpec{
+			Strategy:     RolloutStrategy(src.Rollout.Strategy),
+			PreviewPause: src.Rollout.PreviewPause,
+		}
+		if src.Rollout.Steps != nil {
+			dst.Rollout.Steps = make([]CanaryStep, len(src.Rollout.Steps))
+			for i := range src.Rollout.Steps {
+				dst.Rollout.Steps[i] = CanaryStep(src.Rollout.Steps[i])
+			}
+		}
+	}
+	dst.Suspend = (*SuspendSpec)(src.Suspend)
+	dst.Disruption = (*DisruptionSpec)(src.Disruption)
+
+	// Port always mirrors the first port; Ports is only needed for what Port cannot say.
+	dst.Port = 0
+	dst.Ports = nil
+	if len(src.Ports) > 0 {
+		dst.Port = src.Ports[0].ContainerPort
+	}
+	if len(src.Ports) > 0 && (portsAsList || !isPortShape(src.Ports)) {
+		dst.Ports = make([]PortSpec, len(src.Ports))
+		for i, p := range src.Ports {
+			dst.Ports[i] = PortSpec{
+				Name:          p.Name,
+				ContainerPort: p.ContainerPort,
+				ServicePort:   p.ServicePort,
+				Protocol:      p.Protocol,
+				AppProtocol:   (*AppProtocol)(p.AppProtocol),
+			}
+		}
+	}
+
+	dst.Storage = (*StorageSpec)(src.Storage)
+	dst.Volumes = nil
+	if src.Volumes != nil {
+		dst.Volumes = make([]VolumeSpec, len(src.Volumes))
+		for i := range src.Volumes {
+			dst.Volumes[i] = VolumeSpec(src.Volumes[i])
+		}
+	}
+	dst.ScratchVolumes = nil
+	if src.ScratchVolumes != nil {
+		dst.ScratchVolumes = make([]ScratchVolumeSpec, len(src.ScratchVolumes))
+		for i := range src.ScratchVolumes {
+			dst.ScratchVolumes[i] = ScratchVolumeSpec(src.ScratchVolumes[i])
+		}
+	}
+
+	dst.InitContainer = nil
+	initContainers := src.InitContainers
+	if len(initContainers) > 0 && !initContainersAsList {
+		dst.InitContainer = (*InitContainerSpec)(&initContainers[0])
+		initContainers = initContainers[1:]
+	}
+	dst.InitContainers = nil
+	for _, c := range initContainers {
+		dst.InitContainers = append(dst.InitContainers, ContainerSpec{
+			Name:          c.Name,
+			Image:         c.Image,
+			Command:       c.Command,
+			Args:          c.Args,
+			Env:           c.Env,
+			EnvFrom:       c.EnvFrom,
+			Ports:         c.Ports,
+			RestartPolicy: c.RestartPolicy,
+			Resources:     c.Resources,
+			VolumeMounts:  c.VolumeMounts,
+		})
+	}
+	dst.Sidecars = nil
+	for _, c := range src.Sidecars {
+		dst.Sidecars = append(dst.Sidecars, ContainerSpec{
+			Name:           c.Name,
+			Image:          c.Image,
+			Command:        c.Command,
+			Args:           c.Args,
+			Env:            c.Env,
+			EnvFrom:        c.EnvFrom,
+			Ports:          c.Ports,
+			Resources:      c.Resources,
+			VolumeMounts:   c.VolumeMounts,
+			LivenessProbe:  c.LivenessProbe,
+			ReadinessProbe: c.ReadinessProbe,
+			StartupProbe:   c.StartupProbe,
+		})
+	}
+
+	dst.NodeSelector = src.NodeSelector
+	dst.Tolerations = src.Tolerations
+	dst.Affinity = src.Affinity
+	dst.TopologySpreadConstraints = src.TopologySpreadConstraints
+	dst.Spread = SpreadPreset(src.Spread)
+	dst.PriorityClassName = src.PriorityClassName
+	dst.SecurityContext = nil
+	if src.SecurityContext != nil {
+		dst.SecurityContext = &SecurityContextSpec{
+			Profile:                SecurityProfile(src.SecurityContext.Profile),
+			RunAsUser:              src.SecurityContext.RunAsUser,
+			RunAsGroup:             src.SecurityContext.RunAsGroup,
+			FSGroup:                src.SecurityContext.FSGroup,
+			ReadOnlyRootFilesystem: src.SecurityContext.ReadOnlyRootFilesystem,
+			AddCapabilities:        src.SecurityContext.AddCapabilities,
+		}
+	}
+}
+
+// isPortShape reports whether the hub ports are what a v1alpha1 Port converts to: none,
+// or a single TCP port named DefaultPortName with no other settings.
+func isPortShape(ports []v1beta1.PortSpec) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	return len(ports) == 1 && ports[0].Name == DefaultPortName && ports[0].Protocol == corev1.ProtocolTCP &&
+		ports[0].ServicePort == 0 && ports[0].AppProtocol == nil
+}
+
+// convertExposeToHub converts the expose settings of a deep-copied spec to v1beta1.
+func convertExposeToHub(src *ExposeSpec) *v1beta1.ExposeSpec {
+	if src == nil {
+		return nil
+	}
+	return &v1beta1.ExposeSpec{
+		Type:      v1beta1.ExposeType(src.Type),
+		Hosts:     src.Hosts,
+		Paths:     src.Paths,
+		ClassName: src.ClassName,
+		TLS:       (*v1beta1.ExposeTLSSpec)(src.TLS),
+		Gateway:   (*v1beta1.GatewayReference)(src.Gateway),
+	}
+}
+
+// convertExposeFromHub converts the v1beta1 expose settings of a deep-copied spec to v1alpha1.
+func convertExposeFromHub(src *v1beta1.ExposeSpec) *ExposeSpec {
+	if src == nil {
+		return nil
+	}
+	return &ExposeSpec{
+		Type:      ExposeType(src.Type),
+		Hosts:     src.Hosts,
+		Paths:     src.Paths,
+		ClassName: src.ClassName,
+		TLS:       (*ExposeTLSSpec)(src.TLS),
+		Gateway:   (*GatewayReference)(src.Gateway),
+	}
+}
+
+// convertStatusToHub copies the v1alpha1 status onto v1beta1, working on a deep copy of
+// src like convertSpecToHub.
+func convertStatusToHub(src *WebAppStatus, dst *v1beta1.WebAppStatus) {
+	src = src.DeepCopy()
+	dst.ObservedGeneration = src.ObservedGeneration
+	dst.AvailableReplicas = src.AvailableReplicas
+	dst.URL = src.URL
+	dst.Service = nil
+	if src.Service != nil {
+		dst.Service = &v1beta1.ServiceStatus{
+			Type:                v1beta1.ServiceType(src.Service.Type),
+			ClusterIP:           src.Service.ClusterIP,
+			LoadBalancerIngress: src.Service.LoadBalancerIngress,
+		}
+	}
+	dst.Image = (*v1beta1.ImageStatus)(src.Image)
+	dst.Storage = nil
+	if src.Storage != nil {
+		dst.Storage = &v1beta1.StorageStatus{Capacity: src.Storage.Capacity}
+		if src.Storage.Volumes != nil {
+			dst.Storage.Volumes = make([]v1beta1.VolumeStatus, len(src.Storage.Volumes))
+			for i := range src.Storage.Volumes {
+				dst.Storage.Volumes[i] = v1beta1.VolumeStatus(src.Storage.Volumes[i])
+			}
+		}
+	}
+	dst.Resources = (*v1beta1.ResourceSummary)(src.Resources)
+	dst.Autoscaling = (*v1beta1.AutoscalingStatus)(src.Autoscaling)
+	dst.Schedule = (*v1beta1.ScheduleStatus)(src.Schedule)
+	dst.Rollout = nil
+	if src.Rollout != nil {
+		dst.Rollout = &v1beta1.RolloutStatus{
+			Strategy:      v1beta1.RolloutStrategy(src.Rollout.Strategy),
+			Phase:         v1beta1.RolloutPhase(src.Rollout.Phase),
+			Revision:      src.Rollout.Revision,
+			CurrentStep:   src.Rollout.CurrentStep,
+			Weight:        src.Rollout.Weight,
+			StepStartedAt: src.Rollout.StepStartedAt,
+			Message:       src.Rollout.Message,
+		}
+	}
+	dst.Conditions = src.Conditions
+}
+
+// convertStatusFromHub copies the v1beta1 status onto v1alpha1, working on a deep copy of
+// src like convertSpecToHub.
+func convertStatusFromHub(src *v1beta1.WebAppStatus, dst *WebAppStatus) {
+	src = src.DeepCopy()
+	dst.ObservedGeneration = src.ObservedGeneration
+	dst.AvailableReplicas = src.AvailableReplicas
+	dst.URL = src.URL
+	dst.Service = nil
+	if src.Service != nil {
+		dst.Service = &ServiceStatus{
+			Type:                ServiceType(src.Service.Type),
+			ClusterIP:           src.Service.ClusterIP,
+			LoadBalancerIngress: src.Service.LoadBalancerIngress,
+		}
+	}
+	dst.Image = (*ImageStatus)(src.Image)
+	dst.Storage = nil
+	if src.Storage != nil {
+		dst.Storage = &StorageStatus{Capacity: src.Storage.Capacity}
+		if src.Storage.Volumes != nil {
+			dst.Storage.Volumes = make([]VolumeStatus, len(src.Storage.Volumes))
+			for i := range src.Storage.Volumes {
+				dst.Storage.Volumes[i] = VolumeStatus(src.Storage.Volumes[i])
+			}
+		}
+	}
+	dst.Resources = (*ResourceSummary)(src.Resources)
+	dst.Autoscaling = (*AutoscalingStatus)(src.Autoscaling)
+	dst.Schedule = (*ScheduleStatus)(src.Schedule)
+	dst.Rollout = nil
+	if src.Rollout != nil {
+		dst.Rollout = &RolloutStatus{
+			Strategy:      RolloutStrategy(src.Rollout.Strategy),
+			Phase:         RolloutPhase(src.Rollout.Phase),
+			Revision:      src.Rollout.Revision,
+			CurrentStep:   src.Rollout.CurrentStep,
+			Weight:        src.Rollout.Weight,
+			StepStartedAt: src.Rollout.StepStartedAt,
+			Message:       src.Rollout.Message,
+		}
+	}
+	dst.Conditions = src.Conditions
+}
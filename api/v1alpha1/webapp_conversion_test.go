@@ -0,0 +1,465 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"cmp"
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/randfill"
+
+	"github.com/54b3r/platform-operator-blueprint/api/v1beta1"
+)
+
+var _ = Describe("WebApp Conversion", func() {
+	var alpha *WebApp
+
+	BeforeEach(func() {
+		always := corev1.ContainerRestartPolicyAlways
+		alpha = &WebApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "conversion-test",
+				Namespace:   "default",
+				Labels:      map[string]string{"team": "platform"},
+				Annotations: map[string]string{"owner": "sre"},
+			},
+			Spec: WebAppSpec{
+				Image:       "nginx:1.25",
+				ImagePolicy: ImagePolicyPinDigest,
+				Replicas:    ptr.To[int32](3),
+				Port:        9090,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+				Autoscaling: &AutoscalingSpec{
+					MinReplicas:                    ptr.To[int32](2),
+					MaxReplicas:                    10,
+					TargetCPUUtilizationPercentage: ptr.To[int32](75),
+					Metrics: []autoscalingv2.MetricSpec{{
+						Type: autoscalingv2.PodsMetricSourceType,
+						Pods: &autoscalingv2.PodsMetricSource{
+							Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+							Target: autoscalingv2.MetricTarget{
+								Type:         autoscalingv2.AverageValueMetricType,
+								AverageValue: ptr.To(resource.MustParse("100")),
+							},
+						},
+					}},
+				},
+				Schedules: []ReplicaSchedule{
+					{Name: "night", Cron: "0 20 * * MON-FRI", TimeZone: "Europe/Berlin", Replicas: 0},
+					{Name: "day", Cron: "0 7 * * MON-FRI", Replicas: 2},
+				},
+				Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+				EnvFrom: []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"},
+					},
+				}},
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(9090)},
+					},
+					PeriodSeconds: 5,
+				},
+				HealthCheck: &HealthCheckSpec{Path: "/healthz"},
+				Disruption:  &DisruptionSpec{MinAvailable: ptr.To(intstr.FromString("50%"))},
+				Expose: &ExposeSpec{
+					Hosts:     []string{"shop.example.com"},
+					Paths:     []string{"/"},
+					TLS:       &ExposeTLSSpec{SecretName: "shop-tls"},
+					ClassName: ptr.To("nginx"),
+				},
+				Service: &ServiceSpec{
+					Type:                  ServiceTypeLoadBalancer,
+					Annotations:           map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				},
+				Storage: &StorageSpec{
+					Size:             resource.MustParse("2Gi"),
+					StorageClassName: ptr.To("fast"),
+					MountPath:        DefaultMountPath,
+				},
+				DriftPolicy: DriftPolicyWarn,
+				Rollout: &RolloutSpec{
+					Strategy: RolloutStrategyCanary,
+					Steps: []CanaryStep{
+						{Weight: 10, Pause: &metav1.Duration{Duration: 5 * time.Minute}},
+						{Weight: 50},
+					},
+				},
+				Suspend: &SuspendSpec{ScaleToZero: true},
+				Volumes: []VolumeSpec{{
+					Name:        "cache",
+					Size:        resource.MustParse("1Gi"),
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					VolumeMode:  ptr.To(corev1.PersistentVolumeFilesystem),
+					MountPath:   "/var/cache/app",
+					SubPath:     "app",
+					ReadOnly:    true,
+				}},
+				ScratchVolumes: []ScratchVolumeSpec{{
+					Name:      "models",
+					Medium:    corev1.StorageMediumMemory,
+					SizeLimit: ptr.To(resource.MustParse("512Mi")),
+					MountPath: "/models",
+				}},
+				InitContainer: &InitContainerSpec{
+					Name:          "fetch-model",
+					Image:         "busybox:1.36",
+					Command:       []string{"sh", "-c"},
+					Args:          []string{"wget -O /data/model.bin http://models/model.bin"},
+					Env:           []corev1.EnvVar{{Name: "MODEL", Value: "small"}},
+					RestartPolicy: &always,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+					},
+					VolumeMounts: []corev1.VolumeMount{{Name: "models", MountPath: "/models"}},
+				},
+				InitContainers: []ContainerSpec{{
+					Name:  "migrate",
+					Image: "migrate:1.0",
+					EnvFrom: []corev1.EnvFromSource{{
+						SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}},
+					}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+				}},
+				Sidecars: []ContainerSpec{{
+					Name:  "log-shipper",
+					Image: "fluent-bit:3.0",
+					Args:  []string{"-c", "/fluent-bit/etc/fluent-bit.conf"},
+					Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 2020}},
+					ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/api/v1/health", Port: intstr.FromInt32(2020)},
+					}},
+				}},
+				NodeSelector: map[string]string{"node-pool": "web"},
+				Tolerations: []corev1.Toleration{{
+					Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule,
+				}},
+				Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"},
+						}}}},
+					},
+				}},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+					MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": "shop"}},
+				}},
+				Spread:            SpreadZone,
+				PriorityClassName: "high-priority",
+				SecurityContext: &SecurityContextSpec{
+					Profile:                SecurityProfileRestricted,
+					RunAsUser:              ptr.To[int64](101),
+					FSGroup:                ptr.To[int64](101),
+					ReadOnlyRootFilesystem: true,
+					AddCapabilities:        []corev1.Capability{"NET_BIND_SERVICE"},
+				},
+			},
+			Status: WebAppStatus{
+				ObservedGeneration: 4,
+				AvailableReplicas:  2,
+				Image: &ImageStatus{
+					Tag:        "nginx:1.25",
+					Digest:     "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac",
+					ResolvedAt: ptr.To(metav1.Now()),
+				},
+				URL: "https://shop.example.com/",
+				Service: &ServiceStatus{
+					Type:                ServiceTypeLoadBalancer,
+					ClusterIP:           "10.96.0.42",
+					LoadBalancerIngress: []string{"203.0.113.10"},
+				},
+				Storage: &StorageStatus{
+					Capacity: ptr.To(resource.MustParse("2Gi")),
+					Volumes: []VolumeStatus{{
+						Name: "cache", ClaimName: "conversion-test-cache", Capacity: ptr.To(resource.MustParse("1Gi")),
+					}},
+				},
+				Autoscaling: &AutoscalingStatus{CurrentReplicas: 2, DesiredReplicas: 3},
+				Schedule: &ScheduleStatus{
+					Active:          "day",
+					Replicas:        ptr.To[int32](2),
+					LastTriggerTime: ptr.To(metav1.Now()),
+					Next:            "night",
+					NextTriggerTime: ptr.To(metav1.NewTime(time.Now().Add(time.Hour))),
+				},
+				Rollout: &RolloutStatus{
+					Strategy:      RolloutStrategyCanary,
+					Phase:         RolloutPhasePaused,
+					Revision:      "5d8f6c",
+					CurrentStep:   1,
+					Weight:        50,
+					StepStartedAt: ptr.To(metav1.Now()),
+					Message:       "waiting to be promoted",
+				},
+				Resources: &ResourceSummary{
+					PodRequests:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+					TotalRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("750m")},
+				},
+				Conditions: []metav1.Condition{{
+					Type:               TypeAvailable,
+					Status:             metav1.ConditionTrue,
+					Reason:             "DeploymentAvailable",
+					Message:            "2 replica(s) available",
+					LastTransitionTime: metav1.Now(),
+				}},
+			},
+		}
+	})
+
+	It("Should round-trip every v1alpha1 field through v1beta1", func() {
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Ports).To(ConsistOf(v1beta1.PortSpec{
+			Name: DefaultPortName, ContainerPort: 9090, Protocol: corev1.ProtocolTCP,
+		}))
+		Expect(hub.Spec.Storage.MountPath).To(Equal(DefaultMountPath))
+		Expect(hub.Spec.InitContainers).To(HaveLen(2))
+		Expect(hub.Spec.Sidecars).To(HaveLen(1))
+		Expect(hub.Annotations).NotTo(HaveKey(InitContainersListAnnotation))
+
+		back := &WebApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(equality.Semantic.DeepEqual(back, alpha)).To(BeTrue(), "got %+v", back)
+	})
+
+	It("Should carry a custom mount path, extra ports and init containers through v1alpha1", func() {
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		hub.Spec.Ports = append(hub.Spec.Ports, v1beta1.PortSpec{
+			Name: "metrics", ContainerPort: 9100, Protocol: corev1.ProtocolTCP,
+		})
+		hub.Spec.Storage.MountPath = "/models"
+		hub.Spec.InitContainers = append(hub.Spec.InitContainers, v1beta1.InitContainerSpec{
+			Name: "migrate", Image: "migrate:1.0",
+		})
+
+		spoke := &WebApp{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.Storage.MountPath).To(Equal("/models"))
+		Expect(spoke.Spec.Ports).To(HaveLen(2))
+		Expect(spoke.Spec.InitContainers).To(HaveLen(2))
+
+		restored := &v1beta1.WebApp{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(equality.Semantic.DeepEqual(restored, hub)).To(BeTrue(), "got %+v", restored)
+	})
+
+	It("Should not share memory between hub and spoke", func() {
+		want := alpha.DeepCopy()
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		*hub.Spec.Replicas = 7
+		hub.Spec.Storage.StorageClassName = ptr.To("other")
+		hub.Spec.InitContainers[0].Command[0] = "changed"
+		hub.Spec.Env[0].Value = "changed"
+		Expect(equality.Semantic.DeepEqual(alpha, want)).To(BeTrue(), "got %+v", alpha)
+
+		back := &WebApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		*back.Spec.Replicas = 9
+		back.Spec.InitContainer.Command[0] = "changed again"
+		Expect(*hub.Spec.Replicas).To(Equal(int32(7)))
+		Expect(hub.Spec.InitContainers[0].Command[0]).To(Equal("changed"))
+	})
+
+	It("Should keep init containers given only as a list in the list", func() {
+		alpha.Spec.InitContainer = nil
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.InitContainers).To(HaveLen(1))
+		Expect(hub.Annotations).To(HaveKey(InitContainersListAnnotation))
+
+		back := &WebApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(back.Annotations).NotTo(HaveKey(InitContainersListAnnotation))
+		Expect(equality.Semantic.DeepEqual(back, alpha)).To(BeTrue(), "got %+v", back)
+	})
+
+	It("Should carry the restart policy, env sources and ports of v1beta1 init containers", func() {
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		hub.Spec.InitContainers[1].RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		hub.Spec.InitContainers[0].Ports = []corev1.ContainerPort{{ContainerPort: 9000}}
+
+		spoke := &WebApp{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.InitContainer.Ports).To(Equal([]corev1.ContainerPort{{ContainerPort: 9000}}))
+		Expect(spoke.Spec.InitContainers).To(HaveLen(1))
+		Expect(spoke.Spec.InitContainers[0].RestartPolicy).To(HaveValue(Equal(corev1.ContainerRestartPolicyAlways)))
+
+		restored := &v1beta1.WebApp{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(equality.Semantic.DeepEqual(restored, hub)).To(BeTrue(), "got %+v", restored)
+	})
+
+	It("Should convert named ports to the v1beta1 ports and back", func() {
+		alpha.Spec.Ports = []PortSpec{
+			{Name: "grpc", ContainerPort: 9090, ServicePort: 443, Protocol: corev1.ProtocolTCP,
+				AppProtocol: ptr.To(AppProtocolGRPC)},
+			{Name: "metrics", ContainerPort: 9100, Protocol: corev1.ProtocolTCP},
+		}
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Ports).To(HaveLen(2))
+		Expect(hub.Spec.Ports[0].AppProtocol).To(HaveValue(Equal(v1beta1.AppProtocolGRPC)))
+
+		back := &WebApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(equality.Semantic.DeepEqual(back, alpha)).To(BeTrue(), "got %+v", back)
+	})
+
+	It("Should keep a single default port given as a list in the list", func() {
+		alpha.Spec.Ports = []PortSpec{{Name: DefaultPortName, ContainerPort: 9090, Protocol: corev1.ProtocolTCP}}
+		hub := &v1beta1.WebApp{}
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Ports).To(HaveLen(1))
+		Expect(hub.Annotations).To(HaveKey(PortsListAnnotation))
+
+		back := &WebApp{}
+		Expect(back.ConvertFrom(hub)).To(Succeed())
+		Expect(back.Annotations).NotTo(HaveKey(PortsListAnnotation))
+		Expect(equality.Semantic.DeepEqual(back, alpha)).To(BeTrue(), "got %+v", back)
+
+		By("serving the port as Port alone once the list is dropped")
+		alpha.Spec.Ports = nil
+		Expect(alpha.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Annotations).NotTo(HaveKey(PortsListAnnotation))
+	})
+
+	It("Should serve a v1alpha1 object as v1beta1 through the API server", func() {
+		alpha.Status = WebAppStatus{}
+		Expect(k8sClient.Create(ctx, alpha)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, alpha)).To(Succeed())
+		})
+
+		hub := &v1beta1.WebApp{}
+		key := types.NamespacedName{Name: alpha.Name, Namespace: alpha.Namespace}
+		Expect(k8sClient.Get(ctx, key, hub)).To(Succeed())
+		Expect(hub.Spec.Image).To(Equal("nginx:1.25"))
+		Expect(hub.Spec.Ports).To(HaveLen(1))
+		Expect(hub.Spec.Ports[0].ContainerPort).To(Equal(int32(9090)))
+		Expect(hub.Spec.InitContainers).To(HaveLen(1))
+		Expect(hub.Spec.InitContainers[0].Name).To(Equal("fetch-model"))
+	})
+})
+
+var _ = Describe("WebApp Conversion fuzzing", func() {
+	const iterations = 1000
+
+	var filler *randfill.Filler
+
+	BeforeEach(func() {
+		seed := GinkgoRandomSeed()
+		filler = fuzzer.FuzzerFor(fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, conversionFuzzerFuncs),
+			rand.NewSource(seed), runtimeserializer.CodecFactory{})
+	})
+
+	It("Should round-trip random v1alpha1 objects through v1beta1", func() {
+		for range iterations {
+			alpha := &WebApp{}
+			filler.Fill(alpha)
+
+			hub := &v1beta1.WebApp{}
+			Expect(alpha.ConvertTo(hub)).To(Succeed())
+			back := &WebApp{}
+			Expect(back.ConvertFrom(hub)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(back, alpha)).To(BeTrue(), "got %+v", back)
+		}
+	})
+
+	It("Should round-trip random v1beta1 objects through v1alpha1", func() {
+		for range iterations {
+			hub := &v1beta1.WebApp{}
+			filler.Fill(hub)
+
+			spoke := &WebApp{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			back := &v1beta1.WebApp{}
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(back, hub)).To(BeTrue(), "got %+v", back)
+		}
+	})
+})
+
+// conversionFuzzerFuncs keep fuzzed objects to values the defaulting webhook and the API
+// server would store: quantities are valid, and the v1alpha1 fields that conversion
+// normalizes hold their normalized values.
+func conversionFuzzerFuncs(_ runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(q *resource.Quantity, c randfill.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1<<40), resource.BinarySI)
+		},
+		func(spec *WebAppSpec, c randfill.Continue) {
+			c.FillNoCustom(spec)
+			// Port mirrors the first named port.
+			if len(spec.Ports) > 0 {
+				spec.Port = spec.Ports[0].ContainerPort
+			}
+			// Only sidecars have probes, which v1beta1 init containers cannot hold.
+			for i := range spec.InitContainers {
+				c := &spec.InitContainers[i]
+				c.LivenessProbe, c.ReadinessProbe, c.StartupProbe = nil, nil, nil
+			}
+			// Sidecars always restart and carry no restart policy of their own.
+			for i := range spec.Sidecars {
+				spec.Sidecars[i].RestartPolicy = nil
+			}
+		},
+		// The API server defaults the storage mount path in both versions.
+		func(spec *StorageSpec, c randfill.Continue) {
+			c.FillNoCustom(spec)
+			spec.MountPath = cmp.Or(spec.MountPath, DefaultMountPath)
+		},
+		func(spec *v1beta1.StorageSpec, c randfill.Continue) {
+			c.FillNoCustom(spec)
+			spec.MountPath = cmp.Or(spec.MountPath, DefaultMountPath)
+		},
+		func(spec *InitContainerSpec, c randfill.Continue) {
+			c.FillNoCustom(spec)
+			if spec.Name == "" {
+				spec.Name = DefaultInitContainerName
+			}
+		},
+		func(spec *v1beta1.InitContainerSpec, c randfill.Continue) {
+			c.FillNoCustom(spec)
+			if spec.Name == "" {
+				spec.Name = DefaultInitContainerName
+			}
+		},
+	}
+}
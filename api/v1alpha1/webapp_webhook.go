@@ -0,0 +1,821 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/54b3r/platform-operator-blueprint/internal/schedule"
+)
+
+// webapplog is the logger used by the WebApp admission webhooks.
+var webapplog = logf.Log.WithName("webapp-resource")
+
+// Default values applied to a WebAppSpec. The defaulting webhook and the controller
+// share these so that a WebApp behaves the same whether or not webhooks are enabled.
+const (
+	// DefaultReplicas is the replica count used when Spec.Replicas is unset.
+	DefaultReplicas int32 = 1
+
+	// DefaultMinReplicas is the autoscaler lower bound used when Spec.Autoscaling.MinReplicas is unset.
+	DefaultMinReplicas int32 = 1
+
+	// DefaultExposePath is the path routed to the WebApp when Spec.Expose.Paths is empty.
+	DefaultExposePath = "/"
+
+	// DefaultPort is the container port used when Spec.Port is unset.
+	DefaultPort int32 = 8080
+
+	// DefaultInitContainerName is the init container name used when Spec.InitContainer.Name is unset.
+	DefaultInitContainerName = "init"
+
+	// MainContainerName is the name of the application container in the managed pod template.
+	// Other containers in the pod must not reuse it.
+	MainContainerName = "webapp"
+
+	// StorageVolumeName is the pod volume name used for the Spec.Storage shorthand.
+	// Entries in Spec.Volumes must not reuse it while Spec.Storage is set.
+	StorageVolumeName = "data"
+)
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks for WebApp
+// with the manager's webhook server.
+func (r *WebApp) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(&WebAppCustomDefaulter{}).
+		WithValidator(&WebAppCustomValidator{}).
+		Complete()
+}
+
+// Needed so the API server calls the defaulting webhook on create and update.
+// +kubebuilder:webhook:path=/mutate-app-54b3r-io-v1alpha1-webapp,mutating=true,failurePolicy=fail,sideEffects=None,groups=app.54b3r.io,resources=webapps,verbs=create;update,versions=v1alpha1,name=mwebapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// WebAppCustomDefaulter fills in unset WebAppSpec fields at admission time.
+// It applies the same defaults the controller would otherwise apply during reconcile,
+// so the stored object reflects what actually runs.
+// +kubebuilder:object:generate=false
+type WebAppCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &WebAppCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *WebAppCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	webapp, ok := obj.(*WebApp)
+	if !ok {
+		return fmt.Errorf("expected a WebApp object but got %T", obj)
+	}
+	webapplog.Info("defaulting", "name", webapp.GetName())
+
+	webapp.Spec.ApplyDefaults()
+	return nil
+}
+
+// ApplyDefaults sets unset fields of the spec to their default values.
+// It is idempotent and safe to call on a copy of the spec at reconcile time.
+func (s *WebAppSpec) ApplyDefaults() {
+	if s.Replicas == nil {
+		replicas := DefaultReplicas
+		s.Replicas = &replicas
+	}
+	for i := range s.Ports {
+		if s.Ports[i].Protocol == "" {
+			s.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+	if len(s.Ports) > 0 {
+		s.Port = s.Ports[0].ContainerPort
+	}
+	if s.Port == 0 {
+		s.Port = DefaultPort
+	}
+	if s.Service != nil && s.Service.Type == "" {
+		s.Service.Type = ServiceTypeClusterIP
+	}
+	if s.Storage != nil && s.Storage.MountPath == "" {
+		s.Storage.MountPath = DefaultMountPath
+	}
+	if s.InitContainer != nil && s.InitContainer.Name == "" {
+		s.InitContainer.Name = DefaultInitContainerName
+	}
+	if s.Autoscaling != nil && s.Autoscaling.MinReplicas == nil {
+		minReplicas := DefaultMinReplicas
+		s.Autoscaling.MinReplicas = &minReplicas
+	}
+	if s.Expose != nil && len(s.Expose.Paths) == 0 {
+		s.Expose.Paths = []string{DefaultExposePath}
+	}
+	if s.ImagePolicy == "" {
+		s.ImagePolicy = ImagePolicyTag
+	}
+	if s.DriftPolicy == "" {
+		s.DriftPolicy = DriftPolicyEnforce
+	}
+	if s.SecurityContext != nil && s.SecurityContext.Profile == "" {
+		s.SecurityContext.Profile = SecurityProfileRestricted
+	}
+	if s.Rollout != nil && s.Rollout.Strategy == "" {
+		s.Rollout.Strategy = RolloutStrategyRollingUpdate
+	}
+	for i := range s.Volumes {
+		if len(s.Volumes[i].AccessModes) == 0 {
+			s.Volumes[i].AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
+	}
+}
+
+// Needed so the API server calls the validating webhook on create and update.
+// +kubebuilder:webhook:path=/validate-app-54b3r-io-v1alpha1-webapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=app.54b3r.io,resources=webapps,verbs=create;update,versions=v1alpha1,name=vwebapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// WebAppCustomValidator rejects invalid WebApp specs at admission time, so that
+// mistakes surface on `kubectl apply` rather than as a Degraded condition mid-reconcile.
+// +kubebuilder:object:generate=false
+type WebAppCustomValidator struct{}
+
+var _ webhook.CustomValidator = &WebAppCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *WebAppCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	webapp, ok := obj.(*WebApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a WebApp object but got %T", obj)
+	}
+	webapplog.Info("validating create", "name", webapp.GetName())
+
+	return warningsForWebAppSpec(&webapp.Spec), validateWebApp(webapp)
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *WebAppCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	webapp, ok := newObj.(*WebApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a WebApp object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*WebApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a WebApp object for the oldObj but got %T", oldObj)
+	}
+	webapplog.Info("validating update", "name", webapp.GetName())
+
+	// Metadata-only writes, such as the controller adding or removing its finalizer, must
+	// succeed even when the stored spec fails a rule added after it was admitted;
+	// otherwise a WebApp could never finish deleting.
+	if webapp.DeletionTimestamp != nil || equality.Semantic.DeepEqual(old.Spec, webapp.Spec) {
+		return nil, nil
+	}
+	return warningsForWebAppSpec(&webapp.Spec), validateWebAppUpdate(old, webapp)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+// Deletion is always allowed; the finalizer handles cleanup.
+func (v *WebAppCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateWebApp returns an Invalid API error listing every problem with the spec,
+// or nil if the spec is valid.
+func validateWebApp(webapp *WebApp) error {
+	errs := validateWebAppSpec(&webapp.Spec, field.NewPath("spec"))
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("WebApp").GroupKind(), webapp.Name, errs)
+}
+
+// warningsForWebAppSpec returns admission warnings for specs that are valid but will
+// not behave as the user probably expects.
+func warningsForWebAppSpec(spec *WebAppSpec) admission.Warnings {
+	var warnings admission.Warnings
+	if spec.Autoscaling == nil {
+		return warnings
+	}
+
+	// Utilization targets are a percentage of the request, so without one the
+	// autoscaler reports the metric as missing and never scales.
+	if spec.Autoscaling.TargetCPUUtilizationPercentage != nil {
+		if _, ok := spec.Resources.Requests[corev1.ResourceCPU]; !ok {
+			warnings = append(warnings,
+				"spec.autoscaling.targetCPUUtilizationPercentage has no effect without spec.resources.requests.cpu")
+		}
+	}
+	if spec.Autoscaling.TargetMemoryUtilizationPercentage != nil {
+		if _, ok := spec.Resources.Requests[corev1.ResourceMemory]; !ok {
+			warnings = append(warnings,
+				"spec.autoscaling.targetMemoryUtilizationPercentage has no effect without spec.resources.requests.memory")
+		}
+	}
+	if spec.Replicas != nil && *spec.Replicas != DefaultReplicas {
+		warnings = append(warnings, "spec.replicas is ignored while spec.autoscaling is set")
+	}
+	return warnings
+}
+
+// validateWebAppUpdate runs validateWebApp and additionally rejects changes that cannot
+// be applied to existing child resources, such as shrinking the storage volume.
+func validateWebAppUpdate(old, webapp *WebApp) error {
+	errs := validateWebAppSpec(&webapp.Spec, field.NewPath("spec"))
+	if old.Spec.Storage != nil && webapp.Spec.Storage != nil &&
+		webapp.Spec.Storage.Size.Cmp(old.Spec.Storage.Size) < 0 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "storage", "size"),
+			fmt.Sprintf("volumes cannot shrink: %s is smaller than the current %s",
+				webapp.Spec.Storage.Size.String(), old.Spec.Storage.Size.String())))
+	}
+	for i, volume := range webapp.Spec.Volumes {
+		idx := slices.IndexFunc(old.Spec.Volumes, func(v VolumeSpec) bool { return v.Name == volume.Name })
+		if idx >= 0 && volume.Size.Cmp(old.Spec.Volumes[idx].Size) < 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "volumes").Index(i).Child("size"),
+				fmt.Sprintf("volumes cannot shrink: %s is smaller than the current %s",
+					volume.Size.String(), old.Spec.Volumes[idx].Size.String())))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("WebApp").GroupKind(), webapp.Name, errs)
+}
+
+// validateWebAppSpec checks the rules that the CRD schema cannot express on its own.
+func validateWebAppSpec(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if strings.TrimSpace(spec.Image) == "" {
+		errs = append(errs, field.Required(fldPath.Child("image"), "image must not be empty"))
+	}
+	for i, ref := range spec.ImagePullSecrets {
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			errs = append(errs, field.Invalid(fldPath.Child("imagePullSecrets").Index(i).Child("name"), ref.Name, msg))
+		}
+	}
+	if spec.Replicas != nil && *spec.Replicas < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("replicas"), *spec.Replicas, "must be greater than or equal to 0"))
+	}
+	errs = append(errs, validateResources(&spec.Resources, fldPath.Child("resources"))...)
+	errs = append(errs, validateProbe(spec.LivenessProbe, fldPath.Child("livenessProbe"), true)...)
+	errs = append(errs, validateProbe(spec.ReadinessProbe, fldPath.Child("readinessProbe"), false)...)
+	errs = append(errs, validateProbe(spec.StartupProbe, fldPath.Child("startupProbe"), true)...)
+	if spec.Port != 0 && len(spec.Ports) == 0 {
+		for _, msg := range validation.IsValidPortNum(int(spec.Port)) {
+			errs = append(errs, field.Invalid(fldPath.Child("port"), spec.Port, msg))
+		}
+	}
+	errs = append(errs, validatePorts(spec, fldPath.Child("ports"))...)
+	if spec.Service != nil {
+		errs = append(errs, validateService(spec.Service, fldPath.Child("service"))...)
+	}
+	if spec.Storage != nil && spec.Storage.Size.Sign() <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("storage", "size"), spec.Storage.Size.String(),
+			"must be greater than zero"))
+	}
+	errs = append(errs, validateSchedules(spec, fldPath.Child("schedules"))...)
+	errs = append(errs, validateVolumes(spec, fldPath)...)
+	if spec.InitContainer != nil {
+		errs = append(errs, validateInitContainer(spec.InitContainer, fldPath.Child("initContainer"))...)
+	}
+	errs = append(errs, validateContainers(spec, fldPath)...)
+	if spec.Expose != nil {
+		errs = append(errs, validateExpose(spec.Expose, fldPath.Child("expose"))...)
+	}
+	if spec.Disruption != nil {
+		errs = append(errs, validateIntOrPercent(spec.Disruption.MinAvailable,
+			fldPath.Child("disruption", "minAvailable"))...)
+		errs = append(errs, validateIntOrPercent(spec.Disruption.MaxUnavailable,
+			fldPath.Child("disruption", "maxUnavailable"))...)
+	}
+	if spec.Rollout != nil {
+		errs = append(errs, validateRollout(spec.Rollout, fldPath.Child("rollout"))...)
+	}
+	errs = append(errs, validateScheduling(spec, fldPath)...)
+	if spec.SecurityContext != nil {
+		errs = append(errs, validateSecurityContext(spec.SecurityContext, fldPath.Child("securityContext"))...)
+	}
+	return errs
+}
+
+// validateSecurityContext checks that the overrides of the Restricted profile still pass
+// the restricted Pod Security Standard, which admission would otherwise reject pod by pod.
+func validateSecurityContext(spec *SecurityContextSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	restricted := spec.Profile != SecurityProfileNone
+	if restricted && spec.RunAsUser != nil && *spec.RunAsUser == 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("runAsUser"), *spec.RunAsUser,
+			"must not be 0 with the Restricted profile; use profile None to run as root"))
+	}
+	seen := map[corev1.Capability]bool{}
+	for i, capability := range spec.AddCapabilities {
+		capPath := fldPath.Child("addCapabilities").Index(i)
+		if seen[capability] {
+			errs = append(errs, field.Duplicate(capPath, capability))
+		}
+		seen[capability] = true
+		if restricted && capability != "NET_BIND_SERVICE" {
+			errs = append(errs, field.Invalid(capPath, capability,
+				"only NET_BIND_SERVICE may be added with the Restricted profile"))
+		}
+	}
+	return errs
+}
+
+// validateScheduling checks the node selector, tolerations, topology spread constraints
+// and priority class of the pods. Affinity is left to the Deployment's own validation.
+func validateScheduling(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	errs := metav1validation.ValidateLabels(spec.NodeSelector, fldPath.Child("nodeSelector"))
+
+	for i, t := range spec.Tolerations {
+		tPath := fldPath.Child("tolerations").Index(i)
+		if t.Key != "" {
+			for _, msg := range validation.IsQualifiedName(t.Key) {
+				errs = append(errs, field.Invalid(tPath.Child("key"), t.Key, msg))
+			}
+		} else if t.Operator != corev1.TolerationOpExists {
+			errs = append(errs, field.Invalid(tPath.Child("operator"), t.Operator,
+				"must be Exists when key is empty, to tolerate every taint"))
+		}
+		if t.Operator == corev1.TolerationOpExists && t.Value != "" {
+			errs = append(errs, field.Invalid(tPath.Child("value"), t.Value, "must be empty when operator is Exists"))
+		}
+		if t.TolerationSeconds != nil && t.Effect != corev1.TaintEffectNoExecute {
+			errs = append(errs, field.Invalid(tPath.Child("effect"), t.Effect,
+				"must be NoExecute when tolerationSeconds is set"))
+		}
+	}
+
+	pairs := map[string]bool{}
+	for i, c := range spec.TopologySpreadConstraints {
+		cPath := fldPath.Child("topologySpreadConstraints").Index(i)
+		if c.MaxSkew <= 0 {
+			errs = append(errs, field.Invalid(cPath.Child("maxSkew"), c.MaxSkew, "must be greater than zero"))
+		}
+		if c.TopologyKey == "" {
+			errs = append(errs, field.Required(cPath.Child("topologyKey"), "must name a node label"))
+		}
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule && c.WhenUnsatisfiable != corev1.ScheduleAnyway {
+			errs = append(errs, field.NotSupported(cPath.Child("whenUnsatisfiable"), c.WhenUnsatisfiable,
+				[]corev1.UnsatisfiableConstraintAction{corev1.DoNotSchedule, corev1.ScheduleAnyway}))
+		}
+		pair := c.TopologyKey + "/" + string(c.WhenUnsatisfiable)
+		if pairs[pair] {
+			errs = append(errs, field.Duplicate(cPath, pair))
+		}
+		pairs[pair] = true
+	}
+
+	if spec.PriorityClassName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.PriorityClassName) {
+			errs = append(errs, field.Invalid(fldPath.Child("priorityClassName"), spec.PriorityClassName, msg))
+		}
+	}
+	return errs
+}
+
+// validateRollout checks that Canary rollouts have steps with increasing weights and that
+// the settings of one strategy are not set for another.
+func validateRollout(spec *RolloutSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.Strategy == RolloutStrategyCanary && len(spec.Steps) == 0 {
+		errs = append(errs, field.Required(fldPath.Child("steps"), "a Canary rollout needs at least one step"))
+	}
+	if spec.Strategy != RolloutStrategyCanary && len(spec.Steps) > 0 {
+		errs = append(errs, field.Forbidden(fldPath.Child("steps"), "only allowed with strategy Canary"))
+	}
+	if spec.Strategy != RolloutStrategyBlueGreen && spec.PreviewPause != nil {
+		errs = append(errs, field.Forbidden(fldPath.Child("previewPause"), "only allowed with strategy BlueGreen"))
+	}
+	if spec.PreviewPause != nil && spec.PreviewPause.Duration < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("previewPause"), spec.PreviewPause.Duration.String(),
+			"must not be negative"))
+	}
+	for i, step := range spec.Steps {
+		stepPath := fldPath.Child("steps").Index(i)
+		if i > 0 && step.Weight <= spec.Steps[i-1].Weight {
+			errs = append(errs, field.Invalid(stepPath.Child("weight"), step.Weight,
+				fmt.Sprintf("must be greater than the weight %d of the previous step", spec.Steps[i-1].Weight)))
+		}
+		if step.Pause != nil && step.Pause.Duration < 0 {
+			errs = append(errs, field.Invalid(stepPath.Child("pause"), step.Pause.Duration.String(),
+				"must not be negative"))
+		}
+	}
+	return errs
+}
+
+// validateSchedules checks that every schedule has a unique name, a cron expression that
+// fires and a known time zone, and that schedules are not combined with autoscaling.
+func validateSchedules(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(spec.Schedules) > 0 && spec.Autoscaling != nil {
+		errs = append(errs, field.Forbidden(fldPath, "cannot be combined with autoscaling"))
+	}
+	names := map[string]bool{}
+	for i, s := range spec.Schedules {
+		schedulePath := fldPath.Index(i)
+		if names[s.Name] {
+			errs = append(errs, field.Duplicate(schedulePath.Child("name"), s.Name))
+		}
+		names[s.Name] = true
+		if s.Replicas < 0 {
+			errs = append(errs, field.Invalid(schedulePath.Child("replicas"), s.Replicas,
+				"must be greater than or equal to 0"))
+		}
+		if parsed, err := schedule.Parse(s.Cron, time.UTC); err != nil {
+			errs = append(errs, field.Invalid(schedulePath.Child("cron"), s.Cron, err.Error()))
+		} else if parsed.Next(time.Now()).IsZero() {
+			errs = append(errs, field.Invalid(schedulePath.Child("cron"), s.Cron, "never fires"))
+		}
+		if _, err := time.LoadLocation(s.TimeZone); err != nil || s.TimeZone == "Local" {
+			errs = append(errs, field.Invalid(schedulePath.Child("timeZone"), s.TimeZone,
+				"must be an IANA time zone name such as Europe/Berlin"))
+		}
+	}
+	return errs
+}
+
+// validateVolumes checks that every volume has a valid, unique name and a positive size,
+// and that no two volumes, including the Storage shorthand and scratch volumes, share a
+// mount path.
+func validateVolumes(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+	if spec.Storage != nil {
+		names[StorageVolumeName] = true
+		mountPaths[path.Clean(cmp.Or(spec.Storage.MountPath, DefaultMountPath))] = true
+	}
+	for i, volume := range spec.Volumes {
+		volPath := fldPath.Child("volumes").Index(i)
+		for _, msg := range validation.IsDNS1123Label(volume.Name) {
+			errs = append(errs, field.Invalid(volPath.Child("name"), volume.Name, msg))
+		}
+		if names[volume.Name] {
+			errs = append(errs, field.Duplicate(volPath.Child("name"), volume.Name))
+		}
+		names[volume.Name] = true
+		if volume.Size.Sign() <= 0 {
+			errs = append(errs, field.Invalid(volPath.Child("size"), volume.Size.String(), "must be greater than zero"))
+		}
+		mountPath := path.Clean(volume.MountPath)
+		if mountPaths[mountPath] {
+			errs = append(errs, field.Duplicate(volPath.Child("mountPath"), volume.MountPath))
+		}
+		mountPaths[mountPath] = true
+	}
+	for i, volume := range spec.ScratchVolumes {
+		volPath := fldPath.Child("scratchVolumes").Index(i)
+		for _, msg := range validation.IsDNS1123Label(volume.Name) {
+			errs = append(errs, field.Invalid(volPath.Child("name"), volume.Name, msg))
+		}
+		if names[volume.Name] {
+			errs = append(errs, field.Duplicate(volPath.Child("name"), volume.Name))
+		}
+		names[volume.Name] = true
+		if volume.SizeLimit != nil && volume.SizeLimit.Sign() <= 0 {
+			errs = append(errs, field.Invalid(volPath.Child("sizeLimit"), volume.SizeLimit.String(),
+				"must be greater than zero"))
+		}
+		if volume.MountPath == "" {
+			continue
+		}
+		mountPath := path.Clean(volume.MountPath)
+		if mountPaths[mountPath] {
+			errs = append(errs, field.Duplicate(volPath.Child("mountPath"), volume.MountPath))
+		}
+		mountPaths[mountPath] = true
+	}
+	return errs
+}
+
+// validatePorts checks that every port has a valid, unique name, and that no two ports
+// share a container or Service port number for the same protocol. The first port is the
+// one Ingresses and HTTPRoutes route to, so it must be TCP when Expose is set.
+func validatePorts(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	names := map[string]bool{}
+	containerPorts := map[string]bool{}
+	servicePorts := map[string]bool{}
+	for i, port := range spec.Ports {
+		portPath := fldPath.Index(i)
+		for _, msg := range validation.IsValidPortName(port.Name) {
+			errs = append(errs, field.Invalid(portPath.Child("name"), port.Name, msg))
+		}
+		if names[port.Name] {
+			errs = append(errs, field.Duplicate(portPath.Child("name"), port.Name))
+		}
+		names[port.Name] = true
+
+		protocol := cmp.Or(port.Protocol, corev1.ProtocolTCP)
+		for _, msg := range validation.IsValidPortNum(int(port.ContainerPort)) {
+			errs = append(errs, field.Invalid(portPath.Child("containerPort"), port.ContainerPort, msg))
+		}
+		key := fmt.Sprintf("%d/%s", port.ContainerPort, protocol)
+		if containerPorts[key] {
+			errs = append(errs, field.Duplicate(portPath.Child("containerPort"), port.ContainerPort))
+		}
+		containerPorts[key] = true
+
+		servicePort := cmp.Or(port.ServicePort, port.ContainerPort)
+		if port.ServicePort != 0 {
+			for _, msg := range validation.IsValidPortNum(int(port.ServicePort)) {
+				errs = append(errs, field.Invalid(portPath.Child("servicePort"), port.ServicePort, msg))
+			}
+		}
+		key = fmt.Sprintf("%d/%s", servicePort, protocol)
+		if servicePorts[key] {
+			errs = append(errs, field.Duplicate(portPath.Child("servicePort"), servicePort))
+		}
+		servicePorts[key] = true
+
+		if port.AppProtocol != nil && protocol != corev1.ProtocolTCP {
+			errs = append(errs, field.Invalid(portPath.Child("appProtocol"), *port.AppProtocol,
+				"only allowed on TCP ports"))
+		}
+	}
+	if len(spec.Ports) > 0 && spec.Expose != nil && cmp.Or(spec.Ports[0].Protocol, corev1.ProtocolTCP) != corev1.ProtocolTCP {
+		errs = append(errs, field.Invalid(fldPath.Index(0).Child("protocol"), spec.Ports[0].Protocol,
+			"must be TCP, since expose routes HTTP traffic to the first port"))
+	}
+	return errs
+}
+
+// validateService checks the Service annotations, and that the external traffic policy is
+// only set on Services that receive traffic from outside the cluster.
+func validateService(spec *ServiceSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.ExternalTrafficPolicy != "" && spec.Type != ServiceTypeNodePort && spec.Type != ServiceTypeLoadBalancer {
+		errs = append(errs, field.Forbidden(fldPath.Child("externalTrafficPolicy"),
+			"only allowed with type NodePort or LoadBalancer"))
+	}
+	errs = append(errs, apivalidation.ValidateAnnotations(spec.Annotations, fldPath.Child("annotations"))...)
+	return errs
+}
+
+// validateIntOrPercent checks that a value is a non-negative integer or a percentage
+// between 0% and 100%, the forms a PodDisruptionBudget accepts.
+func validateIntOrPercent(value *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if value == nil {
+		return errs
+	}
+
+	if value.Type == intstr.Int {
+		if value.IntVal < 0 {
+			errs = append(errs, field.Invalid(fldPath, value.IntVal, "must be greater than or equal to 0"))
+		}
+		return errs
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+	if !strings.HasSuffix(value.StrVal, "%") || err != nil {
+		errs = append(errs, field.Invalid(fldPath, value.StrVal, "must be an integer or a percentage such as \"50%\""))
+	} else if percent < 0 || percent > 100 {
+		errs = append(errs, field.Invalid(fldPath, value.StrVal, "must be between 0% and 100%"))
+	}
+	return errs
+}
+
+// validateExpose checks that every host is a valid DNS name, optionally with a leading
+// wildcard label, and that hosts are not repeated.
+func validateExpose(spec *ExposeSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, host := range spec.Hosts {
+		hostPath := fldPath.Child("hosts").Index(i)
+		msgs := validation.IsDNS1123Subdomain(host)
+		if strings.HasPrefix(host, "*.") {
+			msgs = validation.IsWildcardDNS1123Subdomain(host)
+		}
+		for _, msg := range msgs {
+			errs = append(errs, field.Invalid(hostPath, host, msg))
+		}
+		if seen[host] {
+			errs = append(errs, field.Duplicate(hostPath, host))
+		}
+		seen[host] = true
+	}
+	return errs
+}
+
+// validateInitContainer checks the init container name and image.
+// An empty name is allowed because it is defaulted to DefaultInitContainerName.
+func validateInitContainer(spec *InitContainerSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if strings.TrimSpace(spec.Image) == "" {
+		errs = append(errs, field.Required(fldPath.Child("image"), "image must not be empty"))
+	}
+	if spec.Name != "" {
+		for _, msg := range validation.IsDNS1123Label(spec.Name) {
+			errs = append(errs, field.Invalid(fldPath.Child("name"), spec.Name, msg))
+		}
+	}
+	if spec.Name == MainContainerName {
+		errs = append(errs, field.Invalid(fldPath.Child("name"), spec.Name,
+			fmt.Sprintf("must not collide with the main container name %q", MainContainerName)))
+	}
+	errs = append(errs, validateResources(&spec.Resources, fldPath.Child("resources"))...)
+	return errs
+}
+
+// validateContainers checks the InitContainers and Sidecars entries: every container of
+// the pod needs a unique name, volume mounts must name a file system volume of the
+// WebApp, only sidecars may have probes, and only init containers a restart policy.
+func validateContainers(spec *WebAppSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	// mountable maps the name of every volume of the WebApp to whether it holds a file
+	// system; block volumes can only be attached to the main container as devices.
+	mountable := map[string]bool{}
+	if spec.Storage != nil {
+		mountable[StorageVolumeName] = true
+	}
+	for _, v := range spec.Volumes {
+		mountable[v.Name] = v.VolumeMode == nil || *v.VolumeMode != corev1.PersistentVolumeBlock
+	}
+	for _, v := range spec.ScratchVolumes {
+		mountable[v.Name] = true
+	}
+
+	names := map[string]bool{MainContainerName: true}
+	if spec.InitContainer != nil {
+		names[cmp.Or(spec.InitContainer.Name, DefaultInitContainerName)] = true
+		errs = append(errs, validateVolumeMounts(spec.InitContainer.VolumeMounts, mountable,
+			fldPath.Child("initContainer", "volumeMounts"))...)
+	}
+
+	for _, list := range []struct {
+		containers []ContainerSpec
+		path       *field.Path
+		sidecar    bool
+	}{
+		{spec.InitContainers, fldPath.Child("initContainers"), false},
+		{spec.Sidecars, fldPath.Child("sidecars"), true},
+	} {
+		for i := range list.containers {
+			c := &list.containers[i]
+			cPath := list.path.Index(i)
+			for _, msg := range validation.IsDNS1123Label(c.Name) {
+				errs = append(errs, field.Invalid(cPath.Child("name"), c.Name, msg))
+			}
+			if names[c.Name] {
+				errs = append(errs, field.Duplicate(cPath.Child("name"), c.Name))
+			}
+			names[c.Name] = true
+			if strings.TrimSpace(c.Image) == "" {
+				errs = append(errs, field.Required(cPath.Child("image"), "image must not be empty"))
+			}
+			if c.RestartPolicy != nil && list.sidecar {
+				errs = append(errs, field.Forbidden(cPath.Child("restartPolicy"), "sidecars always restart"))
+			}
+			errs = append(errs, validateResources(&c.Resources, cPath.Child("resources"))...)
+			errs = append(errs, validateVolumeMounts(c.VolumeMounts, mountable, cPath.Child("volumeMounts"))...)
+			probes := []struct {
+				probe         *corev1.Probe
+				name          string
+				singleSuccess bool
+			}{
+				{c.LivenessProbe, "livenessProbe", true},
+				{c.ReadinessProbe, "readinessProbe", false},
+				{c.StartupProbe, "startupProbe", true},
+			}
+			for _, p := range probes {
+				if p.probe != nil && !list.sidecar {
+					errs = append(errs, field.Forbidden(cPath.Child(p.name), "only allowed on sidecars"))
+					continue
+				}
+				errs = append(errs, validateProbe(p.probe, cPath.Child(p.name), p.singleSuccess)...)
+			}
+		}
+	}
+	return errs
+}
+
+// validateVolumeMounts checks that every mount of a container names a file system volume
+// of the WebApp, found in mountable, and that no two mounts share a mount path.
+func validateVolumeMounts(mounts []corev1.VolumeMount, mountable map[string]bool, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	mountPaths := map[string]bool{}
+	for i, mount := range mounts {
+		mountPath := fldPath.Index(i)
+		fileSystem, ok := mountable[mount.Name]
+		switch {
+		case !ok:
+			errs = append(errs, field.NotFound(mountPath.Child("name"), mount.Name))
+		case !fileSystem:
+			errs = append(errs, field.Invalid(mountPath.Child("name"), mount.Name, "block volumes cannot be mounted"))
+		}
+		if !strings.HasPrefix(mount.MountPath, "/") {
+			errs = append(errs, field.Invalid(mountPath.Child("mountPath"), mount.MountPath, "must be an absolute path"))
+		}
+		if cleaned := path.Clean(mount.MountPath); mountPaths[cleaned] {
+			errs = append(errs, field.Duplicate(mountPath.Child("mountPath"), mount.MountPath))
+		} else {
+			mountPaths[cleaned] = true
+		}
+	}
+	return errs
+}
+
+// validateProbe checks that a probe defines exactly one handler. Liveness and startup
+// probes must also keep successThreshold at 1; the pod API enforces both as well, but
+// only once the Deployment controller tries to create pods.
+func validateProbe(probe *corev1.Probe, fldPath *field.Path, singleSuccess bool) field.ErrorList {
+	var errs field.ErrorList
+	if probe == nil {
+		return errs
+	}
+
+	handlers := 0
+	for _, set := range []bool{
+		probe.Exec != nil, probe.HTTPGet != nil, probe.TCPSocket != nil, probe.GRPC != nil,
+	} {
+		if set {
+			handlers++
+		}
+	}
+	if handlers != 1 {
+		errs = append(errs, field.Invalid(fldPath, handlers,
+			"must specify exactly one of exec, httpGet, tcpSocket or grpc"))
+	}
+	if singleSuccess && probe.SuccessThreshold > 1 {
+		errs = append(errs, field.Invalid(fldPath.Child("successThreshold"), probe.SuccessThreshold, "must be 1"))
+	}
+	return errs
+}
+
+// validateResources rejects negative quantities and requests above their limit.
+// The API server would otherwise accept the WebApp and only fail when the
+// Deployment controller tries to create pods.
+func validateResources(res *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	// Walk the maps in sorted order so the error list is stable across calls.
+	for _, name := range sortedResourceNames(res.Requests) {
+		req := res.Requests[name]
+		reqPath := fldPath.Child("requests").Key(string(name))
+		if req.Sign() < 0 {
+			errs = append(errs, field.Invalid(reqPath, req.String(), "must be greater than or equal to 0"))
+		}
+		if limit, ok := res.Limits[name]; ok && req.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(reqPath, req.String(),
+				fmt.Sprintf("must be less than or equal to the %s limit of %s", name, limit.String())))
+		}
+	}
+	for _, name := range sortedResourceNames(res.Limits) {
+		limit := res.Limits[name]
+		if limit.Sign() < 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("limits").Key(string(name)), limit.String(),
+				"must be greater than or equal to 0"))
+		}
+	}
+	return errs
+}
+
+// sortedResourceNames returns the keys of the resource list in lexical order.
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,478 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("WebApp Webhook", func() {
+	var (
+		obj       *WebApp
+		defaulter WebAppCustomDefaulter
+		validator WebAppCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &WebApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-test",
+				Namespace: "default",
+			},
+			Spec: WebAppSpec{
+				Image: "nginx:1.25",
+			},
+		}
+		defaulter = WebAppCustomDefaulter{}
+		validator = WebAppCustomValidator{}
+	})
+
+	Context("When creating WebApp under Defaulting Webhook", func() {
+		It("Should apply the replica, port and init container name defaults", func() {
+			obj.Spec.InitContainer = &InitContainerSpec{Image: "busybox:1.36"}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Replicas).NotTo(BeNil())
+			Expect(*obj.Spec.Replicas).To(Equal(DefaultReplicas))
+			Expect(obj.Spec.Port).To(Equal(DefaultPort))
+			Expect(obj.Spec.InitContainer.Name).To(Equal(DefaultInitContainerName))
+			Expect(obj.Spec.DriftPolicy).To(Equal(DriftPolicyEnforce))
+			Expect(obj.Spec.ImagePolicy).To(Equal(ImagePolicyTag))
+		})
+
+		It("Should default the autoscaler lower bound", func() {
+			obj.Spec.Autoscaling = &AutoscalingSpec{MaxReplicas: 5}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Autoscaling.MinReplicas).To(HaveValue(Equal(DefaultMinReplicas)))
+		})
+
+		It("Should not override values set by the user", func() {
+			replicas := int32(3)
+			obj.Spec.Replicas = &replicas
+			obj.Spec.Port = 9090
+			obj.Spec.InitContainer = &InitContainerSpec{Name: "fetch-model", Image: "busybox:1.36"}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Replicas).To(Equal(int32(3)))
+			Expect(obj.Spec.Port).To(Equal(int32(9090)))
+			Expect(obj.Spec.InitContainer.Name).To(Equal("fetch-model"))
+		})
+
+		It("Should mirror the first named port into Port and default the service type", func() {
+			obj.Spec.Ports = []PortSpec{{Name: "grpc", ContainerPort: 9090}, {Name: "metrics", ContainerPort: 9100}}
+			obj.Spec.Service = &ServiceSpec{}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Port).To(Equal(int32(9090)))
+			Expect(obj.Spec.Ports[1].Protocol).To(Equal(corev1.ProtocolTCP))
+			Expect(obj.Spec.Service.Type).To(Equal(ServiceTypeClusterIP))
+		})
+	})
+
+	Context("When creating or updating WebApp under Validating Webhook", func() {
+		It("Should admit a valid spec", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("1Gi")}
+			obj.Spec.InitContainer = &InitContainerSpec{Name: "init", Image: "busybox:1.36"}
+
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(validator.ValidateUpdate(ctx, obj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny an empty image", func() {
+			obj.Spec.Image = "  "
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.image"))
+		})
+
+		It("Should deny an invalid image pull secret name", func() {
+			obj.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry-creds"}, {Name: "Registry_Creds"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.imagePullSecrets[1].name"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.imagePullSecrets[0].name"))
+		})
+
+		It("Should deny a storage size of zero", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("0")}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.size"))
+		})
+
+		It("Should deny an init container named after the main container", func() {
+			old := obj.DeepCopy()
+			obj.Spec.InitContainer = &InitContainerSpec{Name: MainContainerName, Image: "busybox:1.36"}
+
+			_, err := validator.ValidateUpdate(ctx, old, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.initContainer.name"))
+		})
+
+		It("Should warn when a utilization target has no matching resource request", func() {
+			obj.Spec.Autoscaling = &AutoscalingSpec{
+				MaxReplicas:                       5,
+				TargetCPUUtilizationPercentage:    ptr.To[int32](70),
+				TargetMemoryUtilizationPercentage: ptr.To[int32](80),
+			}
+			obj.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("targetMemoryUtilizationPercentage")))
+		})
+
+		It("Should deny a probe without exactly one handler", func() {
+			obj.Spec.LivenessProbe = &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet:   &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)},
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)},
+				},
+			}
+			obj.Spec.ReadinessProbe = &corev1.Probe{PeriodSeconds: 5}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.livenessProbe"))
+			Expect(err.Error()).To(ContainSubstring("spec.readinessProbe"))
+		})
+
+		It("Should deny invalid and duplicate expose hosts", func() {
+			obj.Spec.Expose = &ExposeSpec{Hosts: []string{"*.example.com", "Not_A_Host", "*.example.com"}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.expose.hosts[1]"))
+			Expect(err.Error()).To(ContainSubstring("spec.expose.hosts[2]"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.expose.hosts[0]"))
+		})
+
+		It("Should deny a disruption budget that is not a count or a percentage", func() {
+			obj.Spec.Disruption = &DisruptionSpec{MaxUnavailable: ptr.To(intstr.FromString("150%"))}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.disruption.maxUnavailable"))
+
+			obj.Spec.Disruption = &DisruptionSpec{MinAvailable: ptr.To(intstr.FromString("two"))}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.disruption.minAvailable"))
+		})
+
+		It("Should deny shrinking the storage volume but allow growing it", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("2Gi")}
+			smaller := obj.DeepCopy()
+			smaller.Spec.Storage.Size = resource.MustParse("1Gi")
+			larger := obj.DeepCopy()
+			larger.Spec.Storage.Size = resource.MustParse("4Gi")
+
+			_, err := validator.ValidateUpdate(ctx, obj, smaller)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.size"))
+			Expect(validator.ValidateUpdate(ctx, obj, larger)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit metadata-only updates and updates of a deleting WebApp with an invalid spec", func() {
+			obj.Spec.Image = " "
+			withFinalizer := obj.DeepCopy()
+			withFinalizer.Finalizers = []string{"app.54b3r.io/finalizer"}
+			Expect(validator.ValidateUpdate(ctx, obj, withFinalizer)).Error().NotTo(HaveOccurred())
+
+			deleting := withFinalizer.DeepCopy()
+			deleting.DeletionTimestamp = ptr.To(metav1.Now())
+			deleting.Finalizers = nil
+			deleting.Spec.Replicas = ptr.To[int32](-1)
+			Expect(validator.ValidateUpdate(ctx, withFinalizer, deleting)).Error().NotTo(HaveOccurred())
+
+			changed := withFinalizer.DeepCopy()
+			changed.Spec.Replicas = ptr.To[int32](2)
+			_, err := validator.ValidateUpdate(ctx, withFinalizer, changed)
+			Expect(err).To(MatchError(ContainSubstring("spec.image")))
+		})
+
+		It("Should deny duplicate volume names and mount paths", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("1Gi")}
+			obj.Spec.Volumes = []VolumeSpec{
+				{Name: StorageVolumeName, Size: resource.MustParse("1Gi"), MountPath: "/cache"},
+				{Name: "models", Size: resource.MustParse("1Gi"), MountPath: "/data/"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.volumes[0].name"))
+			Expect(err.Error()).To(ContainSubstring("spec.volumes[1].mountPath"))
+		})
+
+		It("Should let init containers mount scratch and storage volumes", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("1Gi")}
+			obj.Spec.ScratchVolumes = []ScratchVolumeSpec{{Name: "models", MountPath: "/models"}}
+			obj.Spec.InitContainer = &InitContainerSpec{
+				Image: "busybox:1.36",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "models", MountPath: "/models"},
+					{Name: StorageVolumeName, MountPath: "/cache"},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.ScratchVolumes = append(obj.Spec.ScratchVolumes, ScratchVolumeSpec{
+				Name: StorageVolumeName, MountPath: "/data/",
+			})
+			obj.Spec.InitContainer.VolumeMounts = append(obj.Spec.InitContainer.VolumeMounts,
+				corev1.VolumeMount{Name: "tmp", MountPath: "/models"})
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.scratchVolumes[1].name: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.scratchVolumes[1].mountPath: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.initContainer.volumeMounts[2].name: Not found"))
+			Expect(err.Error()).To(ContainSubstring("spec.initContainer.volumeMounts[2].mountPath: Duplicate value"))
+		})
+
+		It("Should deny shrinking a named volume", func() {
+			obj.Spec.Volumes = []VolumeSpec{{Name: "models", Size: resource.MustParse("2Gi"), MountPath: "/models"}}
+			smaller := obj.DeepCopy()
+			smaller.Spec.Volumes[0].Size = resource.MustParse("1Gi")
+
+			_, err := validator.ValidateUpdate(ctx, obj, smaller)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.volumes[0].size"))
+		})
+
+		It("Should deny canary steps without increasing weights and settings of another strategy", func() {
+			obj.Spec.Rollout = &RolloutSpec{
+				Strategy:     RolloutStrategyCanary,
+				Steps:        []CanaryStep{{Weight: 50}, {Weight: 20}},
+				PreviewPause: &metav1.Duration{Duration: time.Minute},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.rollout.steps[1].weight"))
+			Expect(err.Error()).To(ContainSubstring("spec.rollout.previewPause"))
+
+			obj.Spec.Rollout = &RolloutSpec{Strategy: RolloutStrategyCanary}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.rollout.steps"))
+		})
+
+		It("Should deny invalid schedules and schedules combined with autoscaling", func() {
+			obj.Spec.Schedules = []ReplicaSchedule{
+				{Name: "night", Cron: "0 20 * * MON-FRI", TimeZone: "Europe/Berlin", Replicas: 0},
+				{Name: "morning", Cron: "0 7 * * MON-FRI", TimeZone: "Europe/Berlin", Replicas: 2},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Schedules[0].Cron = "0 25 * * *"
+			obj.Spec.Schedules[1].Cron = "0 0 30 2 *"
+			obj.Spec.Schedules[1].TimeZone = "Mars/Olympus"
+			obj.Spec.Autoscaling = &AutoscalingSpec{MaxReplicas: 3}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.schedules: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.schedules[0].cron"))
+			Expect(err.Error()).To(ContainSubstring("never fires"))
+			Expect(err.Error()).To(ContainSubstring("spec.schedules[1].timeZone"))
+
+			obj.Spec.Autoscaling = nil
+			obj.Spec.Schedules[0].Cron = "@every 1h"
+			obj.Spec.Schedules[1] = ReplicaSchedule{Name: "morning", Cron: "CRON_TZ=Europe/Berlin 0 7 * * *", Replicas: 2}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("@every is not supported"))
+			Expect(err.Error()).To(ContainSubstring("set timeZone instead"))
+		})
+
+		It("Should deny duplicate container names, unknown volume mounts, probes on init containers and restart policies on sidecars", func() {
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("1Gi")}
+			obj.Spec.InitContainer = &InitContainerSpec{Image: "busybox:1.36"}
+			obj.Spec.InitContainers = []ContainerSpec{{
+				Name:          "migrate",
+				Image:         "migrate:1.0",
+				RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+				VolumeMounts:  []corev1.VolumeMount{{Name: StorageVolumeName, MountPath: "/data"}},
+			}}
+			obj.Spec.Sidecars = []ContainerSpec{{
+				Name:  "log-shipper",
+				Image: "fluent-bit:3.0",
+				ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(2020)},
+				}},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.InitContainers[0].LivenessProbe = obj.Spec.Sidecars[0].ReadinessProbe
+			obj.Spec.InitContainers = append(obj.Spec.InitContainers, ContainerSpec{
+				Name: DefaultInitContainerName, Image: "busybox:1.36",
+			})
+			obj.Spec.Sidecars = append(obj.Spec.Sidecars, ContainerSpec{
+				Name:          MainContainerName,
+				Image:         "envoy:1.30",
+				RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+				VolumeMounts:  []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+			})
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.initContainers[0].livenessProbe: Forbidden"))
+			Expect(err.Error()).To(ContainSubstring("spec.initContainers[1].name: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.sidecars[1].name: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.sidecars[1].volumeMounts[0].name: Not found"))
+			Expect(err.Error()).To(ContainSubstring("spec.sidecars[1].restartPolicy: Forbidden"))
+		})
+
+		It("Should deny duplicate ports and an app protocol on a UDP port", func() {
+			obj.Spec.Ports = []PortSpec{
+				{Name: "web", ContainerPort: 8080, ServicePort: 80},
+				{Name: "web", ContainerPort: 8081, ServicePort: 80},
+				{Name: "dns", ContainerPort: 5353, Protocol: corev1.ProtocolUDP, AppProtocol: ptr.To(AppProtocolHTTP)},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.ports[1].name: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.ports[1].servicePort: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.ports[2].appProtocol"))
+
+			obj.Spec.Ports = obj.Spec.Ports[:1]
+			obj.Spec.Ports[0].Protocol = corev1.ProtocolUDP
+			obj.Spec.Expose = &ExposeSpec{Hosts: []string{"app.example.com"}}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.ports[0].protocol")))
+		})
+
+		It("Should deny an external traffic policy on a ClusterIP service", func() {
+			obj.Spec.Service = &ServiceSpec{
+				Type:                  ServiceTypeClusterIP,
+				ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.service.externalTrafficPolicy: Forbidden"))
+
+			obj.Spec.Service.Type = ServiceTypeLoadBalancer
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny invalid tolerations, spread constraints and priority class names", func() {
+			obj.Spec.NodeSelector = map[string]string{"node-pool": "web"}
+			obj.Spec.Tolerations = []corev1.Toleration{
+				{Operator: corev1.TolerationOpEqual, Value: "web"},
+				{Key: "dedicated", Operator: corev1.TolerationOpExists, Value: "web"},
+				{Key: "dedicated", Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: ptr.To[int64](60)},
+			}
+			constraint := corev1.TopologySpreadConstraint{
+				MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway,
+			}
+			obj.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{constraint, constraint}
+			obj.Spec.PriorityClassName = "High_Priority"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.tolerations[0].operator"))
+			Expect(err.Error()).To(ContainSubstring("spec.tolerations[1].value"))
+			Expect(err.Error()).To(ContainSubstring("spec.tolerations[2].effect"))
+			Expect(err.Error()).To(ContainSubstring("spec.topologySpreadConstraints[1]: Duplicate value"))
+			Expect(err.Error()).To(ContainSubstring("spec.priorityClassName"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.nodeSelector"))
+		})
+
+		It("Should deny root and extra capabilities with the Restricted profile only", func() {
+			obj.Spec.SecurityContext = &SecurityContextSpec{
+				RunAsUser:       ptr.To[int64](0),
+				AddCapabilities: []corev1.Capability{"NET_BIND_SERVICE", "NET_ADMIN"},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.SecurityContext.Profile).To(Equal(SecurityProfileRestricted))
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.securityContext.runAsUser"))
+			Expect(err.Error()).To(ContainSubstring("spec.securityContext.addCapabilities[1]"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.securityContext.addCapabilities[0]"))
+
+			obj.Spec.SecurityContext.Profile = SecurityProfileNone
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny a resource request above its limit", func() {
+			obj.Spec.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			}
+			obj.Spec.InitContainer = &InitContainerSpec{
+				Name:  "init",
+				Image: "busybox:1.36",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.resources.requests[cpu]"))
+			Expect(err.Error()).To(ContainSubstring("spec.initContainer.resources.requests[memory]"))
+		})
+	})
+
+	Context("When submitting WebApps through the API server", func() {
+		It("Should store the defaulted spec", func() {
+			obj.Name = "webhook-defaulted"
+			obj.Spec.InitContainer = &InitContainerSpec{Image: "busybox:1.36"}
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+			})
+
+			stored := &WebApp{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}, stored)).To(Succeed())
+			Expect(*stored.Spec.Replicas).To(Equal(DefaultReplicas))
+			Expect(stored.Spec.InitContainer.Name).To(Equal(DefaultInitContainerName))
+		})
+
+		It("Should reject an invalid spec at create time", func() {
+			obj.Name = "webhook-rejected"
+			obj.Spec.Storage = &StorageSpec{Size: resource.MustParse("0")}
+			obj.Spec.InitContainer = &InitContainerSpec{Name: MainContainerName, Image: "busybox:1.36"}
+
+			err := k8sClient.Create(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.storage.size"))
+			Expect(err.Error()).To(ContainSubstring("spec.initContainer.name"))
+		})
+	})
+})
@@ -0,0 +1,1052 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// WebAppSpec defines the desired state of WebApp.
+// All fields represent intent — the operator reconciles the cluster toward this state.
+//
+// Compared to v1alpha1, ports and init containers are lists and the storage mount
+// path is configurable.
+type WebAppSpec struct {
+	// Image is the container image to run, including tag.
+	// Example: "nginx:1.25"
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// ImagePolicy decides how Image reaches the pod template. Tag (default) passes it
+	// through unchanged. PinDigest resolves the tag to a manifest digest through the
+	// registry API and runs that digest, so every pod runs the same bits until the tag is
+	// resolved again.
+	// +kubebuilder:default=Tag
+	// +optional
+	ImagePolicy ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// ImagePullSecrets name Secrets of type kubernetes.io/dockerconfigjson or
+	// kubernetes.io/dockercfg in the WebApp's namespace that hold registry credentials.
+	// The pods pull their images with them, in addition to those of the namespace's
+	// default ServiceAccount, and PinDigest resolves tags with the same credentials.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Replicas is the desired number of running pod replicas.
+	// Defaults to 1 if not specified. Ignored while Autoscaling is set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Autoscaling enables a HorizontalPodAutoscaler for the Deployment. While set, the
+	// operator no longer writes the Deployment replica count and Replicas is ignored.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// Schedules override Replicas at set times, for example to scale a development
+	// WebApp to zero in the evening and back up in the morning. The schedule that fired
+	// last sets the replica count; before any has fired, Replicas applies. Cannot be
+	// combined with Autoscaling.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Schedules []ReplicaSchedule `json:"schedules,omitempty"`
+
+	// Disruption configures the PodDisruptionBudget that limits voluntary evictions such
+	// as node drains. Without it, a budget allowing one unavailable pod is created while
+	// the Deployment runs more than one replica. No budget exists at one replica or less,
+	// so single-replica WebApps never block a drain.
+	// +optional
+	Disruption *DisruptionSpec `json:"disruption,omitempty"`
+
+	// Resources sets the CPU and memory requests and limits of the main container.
+	// Without requests, pods run in the BestEffort QoS class and are evicted first
+	// under node pressure.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env is a list of environment variables to set in the main container.
+	// Changes to ConfigMaps and Secrets referenced via valueFrom roll the pods.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom populates environment variables in the main container from ConfigMaps
+	// and Secrets. Changes to the referenced objects roll the pods.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Ports are the container ports the application listens on.
+	// Every port is exposed via the managed Service. The first port is the one health
+	// checks, Ingresses and HTTPRoutes use.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Ports []PortSpec `json:"ports,omitempty"`
+
+	// LivenessProbe restarts the main container when it fails.
+	// Takes precedence over the liveness probe generated from HealthCheck.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// ReadinessProbe removes the pod from Service endpoints while it fails, so a replica
+	// only counts as available once the application can actually serve traffic.
+	// Takes precedence over the readiness probe generated from HealthCheck.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// StartupProbe holds off the liveness and readiness probes until it succeeds,
+	// which protects slow-starting applications from being restarted during boot.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// HealthCheck is a shorthand for HTTP liveness and readiness probes against the first entry in Ports.
+	// Explicit LivenessProbe and ReadinessProbe fields override the generated probes.
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// Storage is a shorthand for a single persistent volume named "data", backed by the
+	// claim "<webapp>-pvc". Use Volumes for more volumes or finer control.
+	// +optional
+	Storage *StorageSpec `json:"storage,omitempty"`
+
+	// Volumes are additional persistent volumes, each backed by its own
+	// PersistentVolumeClaim named "<webapp>-<volume>" and mounted in the main container.
+	// Removing a volume from the list unmounts it but keeps its claim, so data is never
+	// deleted by an edit; claims are removed together with the WebApp.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Volumes []VolumeSpec `json:"volumes,omitempty"`
+
+	// ScratchVolumes are emptyDir volumes that live as long as the pod. They share files
+	// between containers, for example a model an init container downloads for the main
+	// container to serve, and are mounted in the main container when MountPath is set.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ScratchVolumes []ScratchVolumeSpec `json:"scratchVolumes,omitempty"`
+
+	// InitContainers are run in order before the main application container.
+	// Useful for setup tasks like downloading models.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	InitContainers []InitContainerSpec `json:"initContainers,omitempty"`
+
+	// Sidecars run alongside the main container for the lifetime of the pod, for example
+	// to ship logs. They run as native sidecars (init containers with restartPolicy
+	// Always, Kubernetes 1.29+): each starts once the init containers have completed and
+	// before the main container, and is stopped after it.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Sidecars []ContainerSpec `json:"sidecars,omitempty"`
+
+	// NodeSelector limits the pods to nodes with these labels, for example a node pool.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations let the pods run on nodes with matching taints.
+	// +listType=atomic
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity sets the node affinity and the pod affinity and anti-affinity of the pods.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints spread the pods across topology domains such as zones.
+	// The label selector usually matches the WebApp's pods, which carry the labels
+	// app.kubernetes.io/name=webapp and app.kubernetes.io/instance=<name>.
+	// +listType=atomic
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Spread adds topology spread constraints for the WebApp's pods. "zone" spreads them
+	// across zones and across nodes, preferring an even spread without blocking
+	// scheduling. A constraint in TopologySpreadConstraints with the same topology key
+	// replaces the preset one.
+	// +optional
+	Spread SpreadPreset `json:"spread,omitempty"`
+
+	// PriorityClassName is the PriorityClass of the pods, which decides their scheduling
+	// order and which pods are preempted first.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// SecurityContext configures the security context of the pods. Without it, every
+	// container runs with the Restricted profile, which passes the restricted Pod
+	// Security Standard.
+	// +optional
+	SecurityContext *SecurityContextSpec `json:"securityContext,omitempty"`
+
+	// Expose makes the WebApp reachable from outside the cluster through an Ingress or
+	// a Gateway API HTTPRoute that routes to the managed Service.
+	// +optional
+	Expose *ExposeSpec `json:"expose,omitempty"`
+
+	// Service configures the managed Service: its type, annotations and external
+	// traffic policy.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// DriftPolicy decides what happens when the live Deployment no longer matches the spec,
+	// for example after a manual `kubectl edit`. Defaults to Enforce.
+	// +kubebuilder:default=Enforce
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Rollout selects how a new pod template is rolled out. Without it, the Deployment
+	// replaces every pod with its default rolling update.
+	// +optional
+	Rollout *RolloutSpec `json:"rollout,omitempty"`
+
+	// Suspend stops the operator from changing the WebApp's child resources, for example
+	// to hold an application still during an incident. The status is still refreshed.
+	// Remove it to resume.
+	// +optional
+	Suspend *SuspendSpec `json:"suspend,omitempty"`
+}
+
+// ImagePolicy selects how the image reference in Spec.Image is used.
+// +kubebuilder:validation:Enum=Tag;PinDigest
+type ImagePolicy string
+
+const (
+	// ImagePolicyTag runs Spec.Image as written.
+	ImagePolicyTag ImagePolicy = "Tag"
+	// ImagePolicyPinDigest runs the digest Spec.Image resolves to, re-resolved periodically.
+	ImagePolicyPinDigest ImagePolicy = "PinDigest"
+)
+
+// DriftPolicy selects how the operator handles changes made to the live Deployment
+// outside of the WebApp spec.
+// +kubebuilder:validation:Enum=Enforce;Warn;Ignore
+type DriftPolicy string
+
+const (
+	// DriftPolicyEnforce reverts drift and reports the reverted fields in the Drifted condition.
+	DriftPolicyEnforce DriftPolicy = "Enforce"
+	// DriftPolicyWarn keeps the drifted Deployment and reports the fields in the Drifted condition.
+	// Changes to the WebApp spec are still rolled out and override the drift.
+	DriftPolicyWarn DriftPolicy = "Warn"
+	// DriftPolicyIgnore keeps the drifted Deployment without reporting it.
+	// Changes to the WebApp spec are still rolled out and override the drift.
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+)
+
+// PortSpec defines a port of the main container and how the Service exposes it.
+type PortSpec struct {
+	// Name identifies the port, and names it on the container and the Service.
+	// Must be an IANA service name: at most 15 lowercase letters, digits and dashes.
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// ContainerPort is the port number the application listens on.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Required
+	ContainerPort int32 `json:"containerPort"`
+
+	// ServicePort is the port number the Service exposes the port on.
+	// Defaults to ContainerPort if not specified.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	ServicePort int32 `json:"servicePort,omitempty"`
+
+	// Protocol is the network protocol of the port.
+	// Defaults to "TCP" if not specified.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +kubebuilder:default=TCP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+
+	// AppProtocol is the application protocol spoken on the port, which lets ingress
+	// controllers and Gateways pick how to talk to the pods: http, h2c (HTTP/2 without
+	// TLS) or grpc. Only allowed on TCP ports.
+	// +optional
+	AppProtocol *AppProtocol `json:"appProtocol,omitempty"`
+}
+
+// AppProtocol is the application protocol of a port.
+// +kubebuilder:validation:Enum=http;h2c;grpc
+type AppProtocol string
+
+const (
+	// AppProtocolHTTP is HTTP/1.1.
+	AppProtocolHTTP AppProtocol = "http"
+	// AppProtocolH2C is HTTP/2 over cleartext.
+	AppProtocolH2C AppProtocol = "h2c"
+	// AppProtocolGRPC is gRPC.
+	AppProtocolGRPC AppProtocol = "grpc"
+)
+
+// ServiceType selects the kind of the managed Service.
+// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer;Headless
+type ServiceType string
+
+const (
+	// ServiceTypeClusterIP exposes the WebApp on a virtual IP inside the cluster.
+	ServiceTypeClusterIP ServiceType = "ClusterIP"
+	// ServiceTypeNodePort also exposes the WebApp on a port of every node.
+	ServiceTypeNodePort ServiceType = "NodePort"
+	// ServiceTypeLoadBalancer also exposes the WebApp through a cloud load balancer.
+	ServiceTypeLoadBalancer ServiceType = "LoadBalancer"
+	// ServiceTypeHeadless creates a Service without a virtual IP, whose DNS name
+	// resolves to the addresses of the ready pods.
+	ServiceTypeHeadless ServiceType = "Headless"
+)
+
+// ServiceSpec configures the Service managed for a WebApp.
+type ServiceSpec struct {
+	// Type selects the kind of Service. Switching to or from Headless recreates the
+	// Service, since the virtual IP of a Service cannot be added or removed.
+	// Defaults to "ClusterIP" if not specified.
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	Type ServiceType `json:"type,omitempty"`
+
+	// Annotations are set on the Service, for example to configure the cloud load balancer.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ExternalTrafficPolicy decides whether traffic from outside the cluster is routed to
+	// pods on other nodes (Cluster) or only to pods on the receiving node (Local), which
+	// keeps the client source IP. Only allowed with type NodePort or LoadBalancer.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+}
+
+// ServiceStatus reports the observed state of the managed Service.
+type ServiceStatus struct {
+	// Type is the kind of the Service.
+	// +optional
+	Type ServiceType `json:"type,omitempty"`
+
+	// ClusterIP is the virtual IP of the Service. Empty for a headless Service.
+	// +optional
+	ClusterIP string `json:"clusterIP,omitempty"`
+
+	// LoadBalancerIngress lists the IP addresses and host names of the load balancer.
+	// Only set once the load balancer of a LoadBalancer Service is provisioned.
+	// +optional
+	LoadBalancerIngress []string `json:"loadBalancerIngress,omitempty"`
+}
+
+// RolloutStrategy selects how a new pod template is rolled out.
+// +kubebuilder:validation:Enum=RollingUpdate;Canary;BlueGreen
+type RolloutStrategy string
+
+const (
+	// RolloutStrategyRollingUpdate replaces the pods with the Deployment's rolling update.
+	RolloutStrategyRollingUpdate RolloutStrategy = "RollingUpdate"
+	// RolloutStrategyCanary runs the new pod template in a second "<webapp>-canary"
+	// Deployment behind the same Service and shifts replicas to it step by step.
+	RolloutStrategyCanary RolloutStrategy = "Canary"
+	// RolloutStrategyBlueGreen runs the new pod template in a parallel "<webapp>-preview"
+	// Deployment, reachable through the "<webapp>-preview" Service, and switches the
+	// main Service over to it once promoted.
+	RolloutStrategyBlueGreen RolloutStrategy = "BlueGreen"
+)
+
+// RolloutSpec configures progressive rollouts of new pod templates.
+type RolloutSpec struct {
+	// Strategy selects how a new pod template is rolled out. Defaults to RollingUpdate.
+	// +kubebuilder:default=RollingUpdate
+	// +optional
+	Strategy RolloutStrategy `json:"strategy,omitempty"`
+
+	// Steps are the Canary steps, run in order. Each step shifts a share of the replicas
+	// to the new pod template and then pauses. Required for Canary.
+	// +optional
+	Steps []CanaryStep `json:"steps,omitempty"`
+
+	// PreviewPause is how long a BlueGreen preview must stay available before the main
+	// Service is switched to it. When unset, the rollout waits to be promoted.
+	// +optional
+	PreviewPause *metav1.Duration `json:"previewPause,omitempty"`
+}
+
+// CanaryStep is a single step of a Canary rollout.
+type CanaryStep struct {
+	// Weight is the share of the replicas, and so of the Service traffic, that runs the
+	// new pod template during this step, in percent.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// Pause is how long to stay at this step once the canary replicas are available.
+	// When unset, the rollout waits to be promoted.
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+}
+
+// SuspendSpec configures a suspended WebApp.
+type SuspendSpec struct {
+	// ScaleToZero also scales the Deployment to zero replicas while the WebApp is
+	// suspended. The previous replica count is restored on resume.
+	// +optional
+	ScaleToZero bool `json:"scaleToZero,omitempty"`
+}
+
+// SecurityProfile selects the security context defaults of the pods.
+// +kubebuilder:validation:Enum=Restricted;None
+type SecurityProfile string
+
+const (
+	// SecurityProfileRestricted runs the pods as a non-root user with the RuntimeDefault
+	// seccomp profile, and every container without privilege escalation and with all
+	// capabilities dropped, as the restricted Pod Security Standard requires.
+	SecurityProfileRestricted SecurityProfile = "Restricted"
+	// SecurityProfileNone sets no defaults, so the containers run as their images specify.
+	// Only the fields of SecurityContextSpec that are set apply.
+	SecurityProfileNone SecurityProfile = "None"
+)
+
+// SecurityContextSpec configures the security context of every container of the WebApp,
+// including init containers and sidecars.
+type SecurityContextSpec struct {
+	// Profile selects the defaults the other fields override. Defaults to Restricted;
+	// None opts out of the defaults.
+	// +kubebuilder:default=Restricted
+	// +optional
+	Profile SecurityProfile `json:"profile,omitempty"`
+
+	// RunAsUser is the user ID the containers run as. Images whose default user is root
+	// need it under Restricted, since the kubelet refuses to start them as non-root
+	// otherwise; it must not be 0 there.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+
+	// RunAsGroup is the primary group ID the containers run as.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+
+	// FSGroup owns the mounted volumes, so a non-root user can write to them.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of every container read-only.
+	// Paths the application writes to then need a volume, such as a scratch volume.
+	// +optional
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+
+	// AddCapabilities are added back after all capabilities are dropped. Restricted
+	// allows only NET_BIND_SERVICE, to listen on ports below 1024.
+	// +listType=set
+	// +optional
+	AddCapabilities []corev1.Capability `json:"addCapabilities,omitempty"`
+}
+
+// SpreadPreset selects a preset of topology spread constraints.
+// +kubebuilder:validation:Enum=zone
+type SpreadPreset string
+
+const (
+	// SpreadZone spreads the pods across zones and, within a zone, across nodes.
+	SpreadZone SpreadPreset = "zone"
+)
+
+// ReplicaSchedule sets the replica count from the time its cron expression fires until
+// another schedule fires.
+type ReplicaSchedule struct {
+	// Name identifies the schedule in the status.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Cron is a five-field cron expression (minute, hour, day of month, month, day of
+	// week) or a macro such as @daily. Example: "0 20 * * MON-FRI"
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// TimeZone is the IANA time zone the cron expression is evaluated in.
+	// Example: "Europe/Berlin". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Replicas is the replica count from the time the schedule fires.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
+// ExposeType selects the kind of object used to expose a WebApp.
+// +kubebuilder:validation:Enum=Ingress;HTTPRoute
+type ExposeType string
+
+const (
+	// ExposeTypeIngress exposes the WebApp through a networking.k8s.io/v1 Ingress.
+	ExposeTypeIngress ExposeType = "Ingress"
+	// ExposeTypeHTTPRoute exposes the WebApp through a gateway.networking.k8s.io/v1 HTTPRoute.
+	ExposeTypeHTTPRoute ExposeType = "HTTPRoute"
+)
+
+// ExposeSpec describes how a WebApp is exposed outside the cluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.type) || self.type != 'HTTPRoute' || has(self.gateway)",message="gateway is required when type is HTTPRoute"
+type ExposeSpec struct {
+	// Type selects between an Ingress and an HTTPRoute. When unset, an HTTPRoute is used
+	// if Gateway is set and the cluster serves the Gateway API, and an Ingress otherwise.
+	// +optional
+	Type ExposeType `json:"type,omitempty"`
+
+	// Hosts are the DNS names the WebApp is reachable at. The first host without a
+	// wildcard is used for Status.URL.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Hosts []string `json:"hosts"`
+
+	// Paths are the URL path prefixes routed to the WebApp.
+	// Defaults to ["/"] if not specified.
+	// +kubebuilder:validation:items:Pattern=`^/`
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// TLS enables HTTPS. For an Ingress the secret is referenced directly; for an
+	// HTTPRoute, TLS is terminated by the Gateway listener and only the URL scheme changes.
+	// +optional
+	TLS *ExposeTLSSpec `json:"tls,omitempty"`
+
+	// ClassName is the IngressClass to use. Only applies to Ingress.
+	// +optional
+	ClassName *string `json:"className,omitempty"`
+
+	// Gateway is the Gateway the HTTPRoute attaches to. Only applies to HTTPRoute.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// ExposeTLSSpec configures TLS for an exposed WebApp.
+type ExposeTLSSpec struct {
+	// SecretName is the Secret holding the TLS certificate and key for Hosts.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// GatewayReference identifies the Gateway (and optionally its listener) an HTTPRoute attaches to.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway. Defaults to the WebApp's namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the Gateway listener to attach to.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// HealthCheckSpec describes an HTTP health endpoint of the application.
+type HealthCheckSpec struct {
+	// Path is the HTTP path that returns a 2xx or 3xx status while the application is healthy.
+	// Example: "/healthz"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+}
+
+// StorageSpec defines the persistent volume claimed for the WebApp and where it is mounted.
+type StorageSpec struct {
+	// Size is the size of the persistent storage volume. It can be increased later if the
+	// StorageClass allows volume expansion, but never decreased.
+	// Example: "1Gi"
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the name of the storage class to use for the persistent storage volume.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// MountPath is where the volume is mounted in the main container.
+	// Defaults to "/data" if not specified.
+	// +kubebuilder:default="/data"
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// InitContainerSpec defines the configuration for an init container
+// that runs before the main application container starts.
+type InitContainerSpec struct {
+	// Name is the name of the init container. Must be unique within the pod.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Image is the container image to run.
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Command overrides the image entrypoint.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments passed to the command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is a list of environment variables to set in the init container.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// RestartPolicy defines the restart behavior of the init container.
+	// Set to "Always" to run as a sidecar container (Kubernetes 1.29+).
+	// If unset, the init container runs once and must complete successfully before
+	// the main container starts.
+	// +optional
+	RestartPolicy *corev1.ContainerRestartPolicy `json:"restartPolicy,omitempty"`
+
+	// Resources sets the CPU and memory requests and limits of the init container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// EnvFrom populates environment variables from ConfigMaps or Secrets.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Ports are the ports the container listens on.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// VolumeMounts mount volumes of the WebApp into the container, by the name of the
+	// volume: "data" for Storage, or the name of an entry in Volumes or ScratchVolumes.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
+
+// ContainerSpec defines an additional container of the pod, run as an init container or
+// as a sidecar.
+type ContainerSpec struct {
+	// Name is the name of the container. Must be unique among all containers of the pod.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Image is the container image to run.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command overrides the image entrypoint.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Args are the arguments passed to the command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env is a list of environment variables to set in the container.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// EnvFrom populates environment variables from ConfigMaps or Secrets.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Ports are the ports the container listens on.
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// Resources sets the CPU and memory requests and limits of the container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// VolumeMounts mount volumes of the WebApp into the container, by the name of the
+	// volume: "data" for Storage, or the name of an entry in Volumes or ScratchVolumes.
+	// +optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// LivenessProbe restarts the container when it fails. Only allowed on sidecars.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// ReadinessProbe gates the pod's readiness on the container. Only allowed on sidecars.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// StartupProbe delays the start of the main container until the sidecar is up.
+	// Only allowed on sidecars.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler managed for a WebApp.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not exceed maxReplicas"
+type AutoscalingSpec struct {
+	// MinReplicas is the lower bound for the number of replicas.
+	// Defaults to 1 if not specified.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper bound for the number of replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization, as a percentage of
+	// the requested CPU, that the autoscaler aims for. Requires CPU requests in Resources.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetMemoryUtilizationPercentage is the average memory utilization, as a percentage
+	// of the requested memory, that the autoscaler aims for. Requires memory requests in Resources.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+
+	// Metrics are additional metrics (pods, object or external) to scale on, passed
+	// through to the HorizontalPodAutoscaler unchanged.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// AutoscalingStatus reports the state of the HorizontalPodAutoscaler managed for a WebApp.
+type AutoscalingStatus struct {
+	// CurrentReplicas is the number of replicas the autoscaler last observed.
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// DesiredReplicas is the number of replicas the autoscaler last calculated.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+}
+
+// DisruptionSpec sets the PodDisruptionBudget for a WebApp. Exactly one field must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.minAvailable) != has(self.maxUnavailable)",message="exactly one of minAvailable or maxUnavailable must be set"
+type DisruptionSpec struct {
+	// MinAvailable is the number or percentage of pods that must stay available during
+	// voluntary disruptions.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods that may be unavailable during
+	// voluntary disruptions.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// VolumeSpec defines a named persistent volume and where it is mounted.
+type VolumeSpec struct {
+	// Name identifies the volume within the WebApp and suffixes its claim name.
+	// Must be a DNS-1123 label.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Size is the requested size of the volume. It can be increased later if the
+	// StorageClass allows volume expansion, but never decreased.
+	// +kubebuilder:validation:Required
+	Size resource.Quantity `json:"size"`
+
+	// StorageClassName is the storage class of the claim. Uses the cluster default if unset.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// AccessModes are the access modes of the claim. Fixed once the claim exists.
+	// Defaults to ["ReadWriteOnce"] if not specified.
+	// +kubebuilder:default={"ReadWriteOnce"}
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+
+	// VolumeMode selects a formatted file system or a raw block device.
+	// Fixed once the claim exists. Defaults to Filesystem.
+	// +optional
+	VolumeMode *corev1.PersistentVolumeMode `json:"volumeMode,omitempty"`
+
+	// MountPath is where the volume is mounted in the main container. For Block volumes
+	// it is the device path instead.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:Required
+	MountPath string `json:"mountPath"`
+
+	// SubPath mounts a sub-directory of the volume instead of its root.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// ReadOnly mounts the volume read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// ScratchVolumeSpec defines an emptyDir volume of the pod.
+type ScratchVolumeSpec struct {
+	// Name identifies the volume within the WebApp, and in the volumeMounts of init
+	// containers and sidecars. Must be a DNS-1123 label.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Medium is where the volume is stored: the node's disk by default, or memory.
+	// +kubebuilder:validation:Enum="";Memory
+	// +optional
+	Medium corev1.StorageMedium `json:"medium,omitempty"`
+
+	// SizeLimit caps the space the volume may use. Memory-backed volumes count towards
+	// the memory limit of the containers.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// MountPath is where the volume is mounted in the main container. The volume is not
+	// mounted in the main container if unset.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+}
+
+// VolumeStatus reports the observed state of one entry of Spec.Volumes.
+type VolumeStatus struct {
+	// Name is the volume name from Spec.Volumes.
+	Name string `json:"name"`
+
+	// ClaimName is the name of the PersistentVolumeClaim backing the volume.
+	ClaimName string `json:"claimName"`
+
+	// Capacity is the size of the bound volume as reported by the claim.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+}
+
+// StorageStatus reports the observed state of the WebApp's persistent volume claim.
+type StorageStatus struct {
+	// Capacity is the size of the bound volume as reported by the claim. It lags behind
+	// the requested size while an expansion is in progress. Only set while Spec.Storage is set.
+	// +optional
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+
+	// Volumes reports every entry of Spec.Volumes.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Volumes []VolumeStatus `json:"volumes,omitempty"`
+}
+
+// ResourceSummary reports the compute resources requested by a WebApp.
+// Pod values follow the Kubernetes effective-request rules: the main container and
+// sidecars (init containers with restartPolicy Always) are summed, and the result is
+// raised to whatever a regular init container needs while it runs next to the
+// sidecars started before it, if that is more.
+type ResourceSummary struct {
+	// PodRequests is the effective resource request of a single pod.
+	// +optional
+	PodRequests corev1.ResourceList `json:"podRequests,omitempty"`
+
+	// PodLimits is the effective resource limit of a single pod.
+	// Only resources that every contributing container limits are reported.
+	// +optional
+	PodLimits corev1.ResourceList `json:"podLimits,omitempty"`
+
+	// TotalRequests is PodRequests multiplied by the desired replica count.
+	// +optional
+	TotalRequests corev1.ResourceList `json:"totalRequests,omitempty"`
+
+	// TotalLimits is PodLimits multiplied by the desired replica count.
+	// +optional
+	TotalLimits corev1.ResourceList `json:"totalLimits,omitempty"`
+}
+
+// WebAppStatus defines the observed state of WebApp.
+// All fields represent runtime observations — never set these from Spec.
+type WebAppStatus struct {
+	// ObservedGeneration is the metadata.generation of the spec that the operator last
+	// reconciled successfully. When it is below metadata.generation, the latest spec
+	// has not been processed yet.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// AvailableReplicas is the number of pods running and ready to serve traffic.
+	// Updated by the operator after each reconcile.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
+	// Resources summarizes the compute resources the WebApp asks for, per pod and in
+	// total across the desired replicas, so dashboards can add them up without
+	// inspecting pod templates.
+	// +optional
+	Resources *ResourceSummary `json:"resources,omitempty"`
+
+	// Autoscaling reports the HorizontalPodAutoscaler's view of the replica count.
+	// Only set while Spec.Autoscaling is set.
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// Schedule reports the replica schedule in effect. Only set while Spec.Schedules is set.
+	// +optional
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+
+	// Image reports the digest the pods run. Only set while Spec.ImagePolicy is PinDigest.
+	// +optional
+	Image *ImageStatus `json:"image,omitempty"`
+
+	// Service reports the type and addresses of the managed Service.
+	// +optional
+	Service *ServiceStatus `json:"service,omitempty"`
+
+	// URL is the external address of the WebApp, set while Spec.Expose is set.
+	// Example: "https://shop.example.com/"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Storage reports the capacity of the persistent volume claims. Only set while
+	// Spec.Storage or Spec.Volumes is set.
+	// +optional
+	Storage *StorageStatus `json:"storage,omitempty"`
+
+	// Rollout reports the progress of a Canary or BlueGreen rollout. Only set while
+	// Spec.Rollout selects one of them.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// Conditions holds the latest available observations of the WebApp's state.
+	// Uses the standard metav1.Condition type for compatibility with kubectl and tooling.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ImageStatus reports the digest an image tag resolved to.
+type ImageStatus struct {
+	// Tag is the image reference from Spec.Image that was resolved.
+	// Example: "nginx:1.25"
+	Tag string `json:"tag"`
+
+	// Digest is the manifest digest the tag resolved to.
+	// Example: "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+	Digest string `json:"digest"`
+
+	// ResolvedAt is when the tag was last resolved in the registry. Unset when Spec.Image
+	// already names a digest.
+	// +optional
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+}
+
+// ScheduleStatus reports which replica schedule is in effect and which fires next.
+type ScheduleStatus struct {
+	// Active is the name of the schedule that fired last. Empty while none has fired
+	// yet, in which case Spec.Replicas applies.
+	// +optional
+	Active string `json:"active,omitempty"`
+
+	// Replicas is the replica count set by the active schedule.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// LastTriggerTime is when the active schedule fired.
+	// +optional
+	LastTriggerTime *metav1.Time `json:"lastTriggerTime,omitempty"`
+
+	// Next is the name of the schedule that fires next.
+	// +optional
+	Next string `json:"next,omitempty"`
+
+	// NextTriggerTime is when the next schedule fires.
+	// +optional
+	NextTriggerTime *metav1.Time `json:"nextTriggerTime,omitempty"`
+}
+
+// RolloutPhase is the phase of a Canary or BlueGreen rollout.
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing means the replicas of the current step are starting.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePaused means the current step is available and the rollout waits for
+	// its pause to pass or to be promoted.
+	RolloutPhasePaused RolloutPhase = "Paused"
+	// RolloutPhasePromoting means the main Deployment is rolling out the new pod template.
+	RolloutPhasePromoting RolloutPhase = "Promoting"
+	// RolloutPhaseCompleted means the main Deployment runs the current pod template.
+	RolloutPhaseCompleted RolloutPhase = "Completed"
+	// RolloutPhaseAborted means the rollout was aborted and the main Deployment keeps the
+	// previous pod template until the spec changes again.
+	RolloutPhaseAborted RolloutPhase = "Aborted"
+)
+
+// RolloutStatus reports the progress of a Canary or BlueGreen rollout.
+type RolloutStatus struct {
+	// Strategy is the strategy of the rollout.
+	Strategy RolloutStrategy `json:"strategy"`
+
+	// Phase is the phase of the rollout.
+	Phase RolloutPhase `json:"phase"`
+
+	// Revision identifies the pod template being rolled out.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// CurrentStep is the index of the current Canary step. It equals the number of
+	// steps once every step has passed.
+	// +optional
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// Weight is the share of the replicas that runs the new pod template, in percent.
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+
+	// StepStartedAt is when the current step became available, from which its pause is counted.
+	// +optional
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
+
+	// Message describes what the rollout is waiting for.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image",description="Container image"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="Desired replicas"
+// +kubebuilder:printcolumn:name="Available",type="integer",JSONPath=".status.availableReplicas",description="Available replicas"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",description="External URL",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WebApp is the Schema for the webapps API.
+// It represents a web application workload managed by the platform-operator,
+// consisting of a Deployment and a Service.
+type WebApp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WebAppSpec   `json:"spec,omitempty"`
+	Status WebAppStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WebAppList contains a list of WebApp.
+type WebAppList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WebApp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WebApp{}, &WebAppList{})
+}
@@ -21,6 +21,9 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"time"
+	// Embed the time zone database, so replica schedules work on images without one.
+	_ "time/tzdata"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+	appv1beta1 "github.com/54b3r/platform-operator-blueprint/api/v1beta1"
 	"github.com/54b3r/platform-operator-blueprint/internal/controller"
 	// +kubebuilder:scaffold:imports
 )
@@ -51,6 +55,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(appv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -63,6 +68,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var imageResolveInterval time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +87,8 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&imageResolveInterval, "image-resolve-interval", controller.DefaultImageResolveInterval,
+		"How often image tags of WebApps with imagePolicy PinDigest are resolved to a digest again.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -203,12 +211,27 @@ func main() {
 	}
 
 	if err := (&controller.WebAppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		APIReader:            mgr.GetAPIReader(),
+		Recorder:             mgr.GetEventRecorderFor("webapp-controller"),
+		ImageResolveInterval: imageResolveInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "WebApp")
 		os.Exit(1)
 	}
+	// Webhooks can be disabled with ENABLE_WEBHOOKS=false when running the manager
+	// locally without serving certificates (e.g. `make run`).
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := (&appv1alpha1.WebApp{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WebApp")
+			os.Exit(1)
+		}
+		if err := (&appv1beta1.WebApp{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WebApp")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -0,0 +1,145 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// fieldManager is the field manager the operator applies child resources as. Server-side
+// apply tracks the fields each manager sets, so the operator owns exactly the fields it
+// applies and leaves everything else to other managers.
+const fieldManager = "webapp-operator"
+
+// fieldConflictsKey is the context key under which a reconcile collects field conflicts.
+type fieldConflictsKey struct{}
+
+// fieldConflicts collects the conflicts server-side apply reported during one reconcile.
+type fieldConflicts struct {
+	messages []string
+}
+
+// withFieldConflicts returns a context that collects the field conflicts of every apply
+// made with it, and the collector itself.
+func withFieldConflicts(ctx context.Context) (context.Context, *fieldConflicts) {
+	conflicts := &fieldConflicts{}
+	return context.WithValue(ctx, fieldConflictsKey{}, conflicts), conflicts
+}
+
+// apply server-side applies obj, which must hold the complete desired state of the fields
+// the operator manages, and sets the WebApp as its controller owner. When another manager
+// owns one of those fields with a different value, the conflict is recorded on the context
+// and the apply is repeated with forced ownership, so the WebApp spec remains the source
+// of truth. On success obj holds the object returned by the API server.
+func (r *WebAppReconciler) apply(ctx context.Context, webapp *appv1alpha1.WebApp, obj client.Object, kind string) error {
+	// Set the WebApp as the owner so the object is garbage-collected on deletion.
+	if err := controllerutil.SetControllerReference(webapp, obj, r.Scheme); err != nil {
+		return fmt.Errorf("setting owner reference on %s: %w", kind, err)
+	}
+	// Apply requests must carry apiVersion and kind, which typed objects leave empty.
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("resolving kind of %s: %w", kind, err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	// Remember the current resource version to tell creates and changes from no-op applies.
+	previous, err := r.resourceVersionOf(ctx, obj, gvk, kind)
+	if err != nil {
+		return err
+	}
+
+	err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager))
+	if apierrors.IsConflict(err) {
+		logf.FromContext(ctx).Info("taking over conflicting fields", "kind", kind, "name", obj.GetName(), "conflict", err.Error())
+		if conflicts, ok := ctx.Value(fieldConflictsKey{}).(*fieldConflicts); ok {
+			conflicts.messages = append(conflicts.messages, fmt.Sprintf("%s %s: %s", kind, obj.GetName(), err.Error()))
+		}
+		r.recordEvent(webapp, corev1.EventTypeWarning, "FieldsTakenOver",
+			"took over fields of %s %s from another manager", kind, obj.GetName())
+		err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		return fmt.Errorf("applying %s: %w", kind, err)
+	}
+
+	switch {
+	case previous == "":
+		recordChildOperation(webapp, kind, "create")
+		r.recordEvent(webapp, corev1.EventTypeNormal, "Created", "created %s %s", kind, obj.GetName())
+	case obj.GetResourceVersion() != previous:
+		recordChildOperation(webapp, kind, "update")
+		r.recordEvent(webapp, corev1.EventTypeNormal, "Updated",
+			"updated %s %s for generation %d", kind, obj.GetName(), webapp.Generation)
+	}
+	return nil
+}
+
+// resourceVersionOf returns the resource version of the object obj describes, or an empty
+// string when it does not exist yet.
+func (r *WebAppReconciler) resourceVersionOf(ctx context.Context, obj client.Object,
+	gvk schema.GroupVersionKind, kind string) (string, error) {
+	var current client.Object
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		current = u
+	} else {
+		o, err := r.Scheme.New(gvk)
+		if err != nil {
+			return "", fmt.Errorf("creating %s object: %w", kind, err)
+		}
+		current = o.(client.Object)
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), current)
+	if apierrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting %s: %w", kind, err)
+	}
+	return current.GetResourceVersion(), nil
+}
+
+// setFieldConflictCondition reports the field conflicts collected during the reconcile.
+// The condition is True while the operator keeps taking fields back from other managers.
+func setFieldConflictCondition(webapp *appv1alpha1.WebApp, conflicts *fieldConflicts) {
+	if len(conflicts.messages) == 0 {
+		setCondition(webapp, appv1alpha1.TypeFieldConflict, metav1.ConditionFalse,
+			"NoConflicts", "no other field manager conflicts with the operator")
+		return
+	}
+	setCondition(webapp, appv1alpha1.TypeFieldConflict, metav1.ConditionTrue,
+		"FieldsTakenOver", strings.Join(conflicts.messages, "; "))
+}
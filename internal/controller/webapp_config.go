@@ -0,0 +1,189 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// configHashAnnotation is set on the pod template to a hash of every ConfigMap and Secret
+// the WebApp references. Any change to that data changes the template, which makes the
+// Deployment roll the pods without a manual `kubectl rollout restart`.
+const configHashAnnotation = "app.54b3r.io/config-hash"
+
+// Field index names used to find the WebApps that reference a given ConfigMap or Secret.
+const (
+	configMapRefIndex = ".spec.configMapRefs"
+	secretRefIndex    = ".spec.secretRefs"
+)
+
+// configMapRefsIndexer indexes a WebApp by the names of the ConfigMaps it references.
+func configMapRefsIndexer(obj client.Object) []string {
+	configMaps, _ := configReferencesForWebApp(&obj.(*appv1alpha1.WebApp).Spec)
+	return configMaps
+}
+
+// secretRefsIndexer indexes a WebApp by the names of the Secrets it references.
+func secretRefsIndexer(obj client.Object) []string {
+	_, secrets := configReferencesForWebApp(&obj.(*appv1alpha1.WebApp).Spec)
+	return secrets
+}
+
+// configReferencesForWebApp returns the sorted, de-duplicated names of the ConfigMaps and
+// Secrets referenced from env and envFrom of the main container, the init containers and
+// the sidecars.
+func configReferencesForWebApp(spec *appv1alpha1.WebAppSpec) (configMaps, secrets []string) {
+	env := spec.Env
+	envFrom := spec.EnvFrom
+	if spec.InitContainer != nil {
+		env = slices.Concat(env, spec.InitContainer.Env)
+		envFrom = slices.Concat(envFrom, spec.InitContainer.EnvFrom)
+	}
+	for _, c := range slices.Concat(spec.InitContainers, spec.Sidecars) {
+		env = slices.Concat(env, c.Env)
+		envFrom = slices.Concat(envFrom, c.EnvFrom)
+	}
+	for _, e := range env {
+		if e.ValueFrom == nil {
+			continue
+		}
+		if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+			configMaps = append(configMaps, ref.Name)
+		}
+		if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+			secrets = append(secrets, ref.Name)
+		}
+	}
+	for _, src := range envFrom {
+		if src.ConfigMapRef != nil {
+			configMaps = append(configMaps, src.ConfigMapRef.Name)
+		}
+		if src.SecretRef != nil {
+			secrets = append(secrets, src.SecretRef.Name)
+		}
+	}
+
+	slices.Sort(configMaps)
+	slices.Sort(secrets)
+	return slices.Compact(configMaps), slices.Compact(secrets)
+}
+
+// configHashForWebApp hashes the data of every ConfigMap and Secret the WebApp references.
+// Secrets are read through the API reader, as their data is not cached. Returns an empty
+// string when nothing is referenced. A missing object hashes to a
+// marker rather than failing, so creating it later also changes the hash and rolls the pods.
+func (r *WebAppReconciler) configHashForWebApp(ctx context.Context, webapp *appv1alpha1.WebApp) (string, error) {
+	configMaps, secrets := configReferencesForWebApp(&webapp.Spec)
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return "", nil
+	}
+
+	h := sha256.New()
+	for _, name := range configMaps {
+		cm := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: webapp.Namespace}, cm)
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(h, "configmap/%s missing\n", name)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("getting configmap %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "configmap/%s\n", name)
+		data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			data[k] = v
+		}
+		hashData(h, data)
+	}
+	for _, name := range secrets {
+		secret := &corev1.Secret{}
+		err := r.apiReader().Get(ctx, types.NamespacedName{Name: name, Namespace: webapp.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			fmt.Fprintf(h, "secret/%s missing\n", name)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("getting secret %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "secret/%s\n", name)
+		hashData(h, secret.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashData writes the entries of data to h in key order. Each value is length-prefixed
+// so that moving bytes between adjacent keys or values always changes the hash.
+func hashData(h hash.Hash, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%d:", k, len(data[k]))
+		h.Write(data[k])
+	}
+}
+
+// webAppsForConfigMap maps a ConfigMap event to reconcile requests for the WebApps referencing it.
+func (r *WebAppReconciler) webAppsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.webAppsReferencing(ctx, configMapRefIndex, obj)
+}
+
+// webAppsForSecret maps a Secret event to reconcile requests for the WebApps referencing it.
+func (r *WebAppReconciler) webAppsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.webAppsReferencing(ctx, secretRefIndex, obj)
+}
+
+// webAppsReferencing lists the WebApps in obj's namespace whose index entry matches obj's name.
+func (r *WebAppReconciler) webAppsReferencing(ctx context.Context, index string, obj client.Object) []reconcile.Request {
+	webapps := &appv1alpha1.WebAppList{}
+	if err := r.List(ctx, webapps,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{index: obj.GetName()},
+	); err != nil {
+		logf.FromContext(ctx).Error(err, "listing webapps for referenced object",
+			"index", index, "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(webapps.Items))
+	for _, webapp := range webapps.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace},
+		})
+	}
+	return requests
+}
@@ -18,20 +18,32 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
@@ -53,6 +65,23 @@ type WebAppReconciler struct {
 	client.Client
 	// Scheme holds the runtime scheme used for setting owner references.
 	Scheme *runtime.Scheme
+	// APIReader reads Secrets straight from the API server. Secrets are only watched
+	// for their metadata, so their data is never cached. Defaults to Client.
+	APIReader client.Reader
+	// Recorder emits Kubernetes Events on WebApps. Events are skipped when it is nil.
+	Recorder record.EventRecorder
+	// ImageResolver resolves image tags to digests for WebApps with the PinDigest image
+	// policy. Defaults to a client of the registry API that signs in with the image pull secrets.
+	ImageResolver ImageResolver
+	// ImageResolveInterval is how often pinned image tags are resolved again.
+	// Defaults to DefaultImageResolveInterval.
+	ImageResolveInterval time.Duration
+	// Clock tells the time for replica schedules, rollout pauses and image resolution.
+	// Defaults to the system clock; tests set a fake one.
+	Clock clock.PassiveClock
+
+	// events suppresses repeated events from a reconcile that keeps failing the same way.
+	events eventLimiter
 }
 
 // Needed to read and manage WebApp resources and their status subresource.
@@ -71,6 +100,30 @@ type WebAppReconciler struct {
 // Needed to create and manage the PersistentVolumeClaim child resource.
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 
+// Needed to check whether a PersistentVolumeClaim's StorageClass allows volume expansion.
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+
+// Needed to create and manage the HorizontalPodAutoscaler child resource.
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+
+// Needed to create and manage the PodDisruptionBudget child resource.
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
+// Needed to create and manage the Ingress or HTTPRoute that exposes the WebApp.
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+// Needed to watch the ConfigMaps and Secrets referenced from env and envFrom, and to
+// hash their data so that configuration changes roll the pods.
+// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch
+
+// Needed to find the image pull secrets of the namespace's default ServiceAccount, whose
+// credentials PinDigest resolves tags with.
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
+
+// Needed to emit Events about what the operator did to a WebApp.
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
 // Needed for leader election to work correctly in multi-replica deployments.
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
@@ -82,13 +135,22 @@ type WebAppReconciler struct {
 // On deletion, the finalizer ensures child resources are cleaned up before
 // the WebApp is removed from the API server.
 //
+// While Spec.Suspend is set, child resources are left alone and only the status is
+// refreshed.
+//
+// Status changes are collected in memory and written once, as a merge patch, when
+// Reconcile returns.
+//
 // The reconciler requeues after requeueAfter to self-heal against drift.
-func (r *WebAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *WebAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := logf.FromContext(ctx)
 
 	// Fetch the WebApp resource. If it no longer exists, nothing to do.
 	webapp := &appv1alpha1.WebApp{}
 	if err := r.Get(ctx, req.NamespacedName, webapp); err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteWebAppMetrics(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -98,13 +160,16 @@ func (r *WebAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if controllerutil.ContainsFinalizer(webapp, webappFinalizer) {
 			log.Info("running finalizer cleanup", "name", webapp.Name)
 			if err := r.cleanupChildResources(ctx, webapp); err != nil {
+				r.recordEvent(webapp, corev1.EventTypeWarning, "CleanupFailed", "finalizer cleanup failed: %v", err)
 				return ctrl.Result{}, fmt.Errorf("finalizer cleanup: %w", err)
 			}
+			r.recordEvent(webapp, corev1.EventTypeNormal, "CleanupComplete", "finalizer cleanup complete")
 			controllerutil.RemoveFinalizer(webapp, webappFinalizer)
 			if err := r.Update(ctx, webapp); err != nil {
 				return ctrl.Result{}, fmt.Errorf("removing finalizer: %w", err)
 			}
 		}
+		deleteWebAppMetrics(webapp.Namespace, webapp.Name)
 		// Requeue not needed — object is being deleted.
 		return ctrl.Result{}, nil
 	}
@@ -119,80 +184,253 @@ func (r *WebAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// Mark the resource as Progressing while we reconcile.
-	if err := r.setCondition(ctx, webapp, appv1alpha1.TypeProgressing, metav1.ConditionTrue,
-		"Reconciling", "reconciliation in progress"); err != nil {
-		return ctrl.Result{}, err
-	}
+	// Collect every status change in memory and write it once, when Reconcile returns.
+	// A failed reconcile is retried, so the WebApp stays Progressing until one succeeds.
+	original := webapp.DeepCopy()
+	defer func() {
+		if err != nil {
+			setCondition(webapp, appv1alpha1.TypeProgressing, metav1.ConditionTrue,
+				"Reconciling", "reconciliation in progress")
+		}
+		if statusErr := r.patchStatus(ctx, original, webapp); statusErr != nil {
+			err = errors.Join(err, statusErr)
+		}
+		recordConditionMetrics(webapp)
+	}()
 
-	// Reconcile the Deployment child resource.
-	if err := r.reconcileDeployment(ctx, webapp); err != nil {
-		_ = r.setCondition(ctx, webapp, appv1alpha1.TypeDegraded, metav1.ConditionTrue,
-			"DeploymentFailed", err.Error())
-		return ctrl.Result{}, fmt.Errorf("reconciling deployment: %w", err)
+	// Work out which replica schedule is in effect; its count replaces Spec.Replicas.
+	if err := r.reconcileSchedule(webapp); err != nil {
+		r.markDegraded(webapp, degradedReason(err, "ScheduleFailed"), err)
+		return ctrl.Result{}, fmt.Errorf("evaluating schedules: %w", err)
 	}
 
-	// Reconcile the Service child resource.
-	if err := r.reconcileService(ctx, webapp); err != nil {
-		_ = r.setCondition(ctx, webapp, appv1alpha1.TypeDegraded, metav1.ConditionTrue,
-			"ServiceFailed", err.Error())
-		return ctrl.Result{}, fmt.Errorf("reconciling service: %w", err)
+	// A suspended WebApp keeps its child resources as they are; only the status is refreshed.
+	suspended := webapp.Spec.Suspend != nil
+	if suspended {
+		if err := r.reconcileSuspended(ctx, webapp); err != nil {
+			r.markDegraded(webapp, "SuspendFailed", err)
+			return ctrl.Result{}, fmt.Errorf("reconciling suspended webapp: %w", err)
+		}
+	} else {
+		r.resumeWebApp(ctx, webapp)
+		if err := r.reconcileChildren(ctx, webapp); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	// Reconcile the Storage child resource.
-	if err := r.reconcileStorage(ctx, webapp); err != nil {
-		_ = r.setCondition(ctx, webapp, appv1alpha1.TypeDegraded, metav1.ConditionTrue,
-			"StorageFailed", err.Error())
-		return ctrl.Result{}, fmt.Errorf("reconciling storage: %w", err)
-	}
 	// Fetch the current Deployment to read available replicas for status.
 	dep := &appsv1.Deployment{}
 	if err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, dep); err != nil {
-		return ctrl.Result{}, fmt.Errorf("fetching deployment for status: %w", err)
-	}
-
-	// Update status with observed replica count and Available condition.
+		if !suspended || !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("fetching deployment for status: %w", err)
+		}
+		// Suspended before the Deployment was first created: there is nothing to observe.
+		setCondition(webapp, appv1alpha1.TypeAvailable, metav1.ConditionFalse, "Suspended",
+			"the webapp was suspended before its deployment was created")
+		setCondition(webapp, appv1alpha1.TypeProgressing, metav1.ConditionFalse, "Suspended",
+			"the webapp was suspended before its deployment was created")
+		setCondition(webapp, appv1alpha1.TypeDegraded, metav1.ConditionFalse, "ReconcileComplete", "no errors")
+		webapp.Status.ObservedGeneration = webapp.Generation
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// Update status with observed replica count and requested resources.
+	// The resource summary is computed from the Deployment's pod template, so it reflects
+	// what is actually scheduled rather than what the spec asks for.
 	webapp.Status.AvailableReplicas = dep.Status.AvailableReplicas
-	available := dep.Status.AvailableReplicas > 0
-	availStatus := metav1.ConditionFalse
-	availReason := "DeploymentUnavailable"
-	availMsg := "no replicas are available yet"
-	if available {
-		availStatus = metav1.ConditionTrue
-		availReason = "DeploymentAvailable"
-		availMsg = fmt.Sprintf("%d replica(s) available", dep.Status.AvailableReplicas)
-	}
-	if err := r.setCondition(ctx, webapp, appv1alpha1.TypeAvailable, availStatus, availReason, availMsg); err != nil {
+	webappReplicasDesired.WithLabelValues(webapp.Namespace, webapp.Name).Set(float64(ptr.Deref(dep.Spec.Replicas, 1)))
+	webappReplicasAvailable.WithLabelValues(webapp.Namespace, webapp.Name).Set(float64(dep.Status.AvailableReplicas))
+	webapp.Status.Resources = resourceSummaryForPod(&dep.Spec.Template.Spec, ptr.Deref(dep.Spec.Replicas, 1))
+
+	// Report the storage capacity and resize progress.
+	// Claims and the autoscaler added to the spec of a suspended WebApp do not exist yet.
+	if err := r.updateStorageStatus(ctx, webapp); err != nil && (!suspended || !apierrors.IsNotFound(err)) {
 		return ctrl.Result{}, err
 	}
-	if err := r.setCondition(ctx, webapp, appv1alpha1.TypeProgressing, metav1.ConditionFalse,
-		"ReconcileComplete", "reconciliation complete"); err != nil {
-		return ctrl.Result{}, err
+
+	// Report the autoscaler's view of the replica count while autoscaling is enabled.
+	webapp.Status.Autoscaling = nil
+	if webapp.Spec.Autoscaling != nil {
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, hpa)
+		switch {
+		case err == nil:
+			webapp.Status.Autoscaling = &appv1alpha1.AutoscalingStatus{
+				CurrentReplicas: hpa.Status.CurrentReplicas,
+				DesiredReplicas: hpa.Status.DesiredReplicas,
+			}
+		case !suspended || !apierrors.IsNotFound(err):
+			return ctrl.Result{}, fmt.Errorf("fetching autoscaler for status: %w", err)
+		}
 	}
-	if err := r.setCondition(ctx, webapp, appv1alpha1.TypeDegraded, metav1.ConditionFalse,
-		"ReconcileComplete", "no errors"); err != nil {
+	// Report the Service type and addresses, such as the load balancer's.
+	if err := r.updateServiceStatus(ctx, webapp); err != nil && (!suspended || !apierrors.IsNotFound(err)) {
 		return ctrl.Result{}, err
 	}
 
+	// Derive Available and Progressing from the rollout rather than from the replica count
+	// alone, and report pods rejected by Pod Security admission or a rollout past its
+	// progress deadline as Degraded.
+	rollout := rolloutStateForDeployment(dep)
+	if suspended && ptr.Deref(dep.Spec.Replicas, 1) == 0 && dep.Annotations[suspendedReplicasAnnotation] != "" {
+		rollout.available, rollout.availableReason = metav1.ConditionFalse, "ScaledToZero"
+		rollout.availableMessage = "the deployment is scaled to zero replicas while the webapp is suspended"
+	}
+	setCondition(webapp, appv1alpha1.TypeAvailable, rollout.available, rollout.availableReason, rollout.availableMessage)
+	setCondition(webapp, appv1alpha1.TypeProgressing, rollout.progressing,
+		rollout.progressingReason, rollout.progressingMessage)
+	if st := webapp.Status.Rollout; st != nil &&
+		(st.Phase == appv1alpha1.RolloutPhaseProgressing || st.Phase == appv1alpha1.RolloutPhasePaused) {
+		setCondition(webapp, appv1alpha1.TypeProgressing, metav1.ConditionTrue, "Rollout"+string(st.Phase), st.Message)
+	}
+	switch {
+	case rollout.podSecurityRejection != "":
+		r.markDegraded(webapp, "PodSecurityRejected", errors.New(rollout.podSecurityRejection))
+	case rollout.stuck:
+		r.markDegraded(webapp, "RolloutStuck", errors.New(rollout.progressingMessage))
+	default:
+		setCondition(webapp, appv1alpha1.TypeDegraded, metav1.ConditionFalse,
+			"ReconcileComplete", "no errors")
+	}
+	webapp.Status.ObservedGeneration = webapp.Generation
+
 	log.Info("reconciliation complete",
 		"name", webapp.Name,
 		"availableReplicas", webapp.Status.AvailableReplicas,
 	)
 
-	// Requeue after requeueAfter to self-heal against any drift not caught by watches.
-	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	// Requeue after requeueAfter to self-heal against any drift not caught by watches,
+	// or earlier when the pause of a rollout step ends or a replica schedule fires.
+	// A suspended rollout does not advance, so its pause does not matter.
+	now := r.now()
+	result.RequeueAfter = requeueAfter
+	if pause := rolloutRequeueAfter(webapp, now); !suspended && pause > 0 && pause < result.RequeueAfter {
+		result.RequeueAfter = pause
+	}
+	if next := scheduleRequeueAfter(webapp, now); next > 0 && next < result.RequeueAfter {
+		result.RequeueAfter = next
+	}
+	return result, nil
+}
+
+// apiReader returns r.APIReader, or r.Client when it is unset.
+func (r *WebAppReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// now returns the current time from r.Clock, or from the system clock when it is unset.
+func (r *WebAppReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
 }
 
-// reconcileDeployment creates or updates the Deployment for the given WebApp.
+// reconcileChildren reconciles every child resource of the WebApp in turn, stopping at
+// the first failure, which is reported as Degraded.
+func (r *WebAppReconciler) reconcileChildren(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	// Child resources are server-side applied; collect the field conflicts they run into.
+	ctx, conflicts := withFieldConflicts(ctx)
+
+	// Reconcile the Deployment child resource.
+	if err := timePhase(webapp, "deployment", func() error { return r.reconcileDeployment(ctx, webapp) }); err != nil {
+		r.markDegraded(webapp, degradedReason(err, "DeploymentFailed"), err)
+		return fmt.Errorf("reconciling deployment: %w", err)
+	}
+
+	// Reconcile the Service child resource.
+	if err := timePhase(webapp, "service", func() error { return r.reconcileService(ctx, webapp) }); err != nil {
+		r.markDegraded(webapp, "ServiceFailed", err)
+		return fmt.Errorf("reconciling service: %w", err)
+	}
+
+	// Reconcile the Storage child resource.
+	if err := timePhase(webapp, "storage", func() error { return r.reconcileStorage(ctx, webapp) }); err != nil {
+		r.markDegraded(webapp, degradedReason(err, "StorageFailed"), err)
+		return fmt.Errorf("reconciling storage: %w", err)
+	}
+
+	// Reconcile the HorizontalPodAutoscaler child resource.
+	if err := timePhase(webapp, "autoscaler", func() error { return r.reconcileAutoscaler(ctx, webapp) }); err != nil {
+		r.markDegraded(webapp, "AutoscalerFailed", err)
+		return fmt.Errorf("reconciling autoscaler: %w", err)
+	}
+
+	// Reconcile the PodDisruptionBudget child resource.
+	if err := timePhase(webapp, "disruptionBudget", func() error { return r.reconcileDisruptionBudget(ctx, webapp) }); err != nil {
+		r.markDegraded(webapp, "DisruptionBudgetFailed", err)
+		return fmt.Errorf("reconciling disruption budget: %w", err)
+	}
+
+	// Reconcile the Ingress or HTTPRoute child resource.
+	var url string
+	if err := timePhase(webapp, "expose", func() (err error) {
+		url, err = r.reconcileExpose(ctx, webapp)
+		return err
+	}); err != nil {
+		r.markDegraded(webapp, "ExposeFailed", err)
+		return fmt.Errorf("reconciling expose: %w", err)
+	}
+	webapp.Status.URL = url
+	setFieldConflictCondition(webapp, conflicts)
+	return nil
+}
+
+// reconcileDeployment server-side applies the Deployment for the given WebApp.
 // It sets an owner reference so the Deployment is garbage-collected with the WebApp.
-// Only the image, replicas, and port fields are updated on an existing Deployment
-// to avoid clobbering fields managed by other controllers (e.g. HPA).
+// The operator owns only the fields it applies, so annotations added by
+// `kubectl rollout restart` and fields set by other controllers are left alone.
+// Changes made to the live Deployment outside the spec are handled per Spec.DriftPolicy.
 func (r *WebAppReconciler) reconcileDeployment(ctx context.Context, webapp *appv1alpha1.WebApp) error {
-	log := logf.FromContext(ctx)
+	// Apply defaults to a copy of the spec. The defaulting webhook normally does this at
+	// admission time; repeating it here keeps behavior identical when webhooks are disabled.
+	spec := webapp.Spec.DeepCopy()
+	spec.ApplyDefaults()
+	if replicas := scheduledReplicas(webapp); replicas != nil {
+		spec.Replicas = replicas
+	}
+	liveness, readiness := probesForWebApp(spec)
+	volumes := storageVolumesForWebApp(webapp.Name, spec)
 
-	replicas := int32(1)
-	if webapp.Spec.Replicas != nil {
-		replicas = *webapp.Spec.Replicas
+	image, err := r.imageForWebApp(ctx, webapp, spec)
+	if err != nil {
+		return err
+	}
+	configHash, err := r.configHashForWebApp(ctx, webapp)
+	if err != nil {
+		return fmt.Errorf("hashing referenced config: %w", err)
+	}
+	var podAnnotations map[string]string
+	if configHash != "" {
+		podAnnotations = map[string]string{configHashAnnotation: configHash}
+	}
+
+	existing := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	} else if err != nil {
+		return fmt.Errorf("getting deployment: %w", err)
+	}
+
+	// With autoscaling, start at the lower bound and let the autoscaler take over from there.
+	// Once the Deployment exists, keep applying the count the autoscaler chose: that shares
+	// ownership with it, whereas dropping the field would reset the Deployment to one replica.
+	replicas := spec.Replicas
+	if spec.Autoscaling != nil {
+		replicas = spec.Autoscaling.MinReplicas
+		if existing != nil && existing.Spec.Replicas != nil {
+			replicas = existing.Spec.Replicas
+		}
+		// On resume from ScaleToZero, go back to the count the autoscaler had chosen.
+		if existing != nil && ptr.Deref(existing.Spec.Replicas, 1) == 0 {
+			if previous, ok := parseSuspendedReplicas(existing.Annotations[suspendedReplicasAnnotation]); ok {
+				replicas = ptr.To(previous)
+			}
+		}
 	}
 
 	desired := &appsv1.Deployment{
@@ -201,154 +439,210 @@ func (r *WebAppReconciler) reconcileDeployment(ctx context.Context, webapp *appv
 			Namespace: webapp.Namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &replicas,
+			Replicas: replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labelsForWebApp(webapp.Name),
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labelsForWebApp(webapp.Name),
+					Labels:      labelsForWebApp(webapp.Name),
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					Volumes: volumesForWebApp(webapp.Name, webapp.Spec.Storage),
+					Volumes: slices.Concat(podVolumesForWebApp(volumes), scratchPodVolumesForWebApp(spec)),
 					Containers: []corev1.Container{
 						{
-							Name:  "webapp",
-							Image: webapp.Spec.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: webapp.Spec.Port,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-							VolumeMounts: volumeMountsForWebApp(webapp.Spec.Storage),
+							Name:           appv1alpha1.MainContainerName,
+							Image:          image,
+							Ports:          containerPortsForWebApp(spec),
+							Env:            spec.Env,
+							EnvFrom:        spec.EnvFrom,
+							Resources:      spec.Resources,
+							LivenessProbe:  liveness,
+							ReadinessProbe: readiness,
+							StartupProbe:   spec.StartupProbe,
+							VolumeMounts:   slices.Concat(volumeMountsForWebApp(volumes), scratchVolumeMountsForWebApp(spec)),
+							VolumeDevices:  volumeDevicesForWebApp(volumes),
 						},
 					},
-					InitContainers: initContainersForWebApp(webapp.Spec.InitContainer),
+					InitContainers:            initContainersForWebApp(spec),
+					ImagePullSecrets:          spec.ImagePullSecrets,
+					NodeSelector:              spec.NodeSelector,
+					Tolerations:               spec.Tolerations,
+					Affinity:                  spec.Affinity,
+					TopologySpreadConstraints: topologySpreadConstraintsForWebApp(webapp.Name, spec),
+					PriorityClassName:         spec.PriorityClassName,
 				},
 			},
 		},
 	}
+	applySecurityContext(&desired.Spec.Template.Spec, spec)
 
-	// Set the WebApp as the owner of the Deployment so it is garbage-collected on deletion.
-	if err := controllerutil.SetControllerReference(webapp, desired, r.Scheme); err != nil {
-		return fmt.Errorf("setting owner reference on deployment: %w", err)
+	appliedHash, err := appliedHashForDeployment(desired, spec.Autoscaling != nil)
+	if err != nil {
+		return err
 	}
-
-	existing := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, existing)
-	if apierrors.IsNotFound(err) {
-		log.Info("creating deployment", "name", webapp.Name)
-		return r.Create(ctx, desired)
+	templateHash, err := templateHashForDeployment(desired)
+	if err != nil {
+		return err
 	}
+	desired.Annotations = map[string]string{
+		appliedHashAnnotation:  appliedHash,
+		templateHashAnnotation: templateHash,
+	}
+
+	// Canary and BlueGreen rollouts keep the main Deployment on its pod template until
+	// the new one is promoted. Deployments applied before the template hash existed
+	// are treated as current.
+	action, err := r.takeRolloutAction(ctx, webapp)
 	if err != nil {
-		return fmt.Errorf("getting deployment: %w", err)
+		return err
+	}
+	strategy := rolloutStrategyForWebApp(spec)
+	if strategy != appv1alpha1.RolloutStrategyRollingUpdate && existing != nil {
+		if current := existing.Annotations[templateHashAnnotation]; current != "" && current != templateHash {
+			return r.reconcileProgressiveRollout(ctx, webapp, spec, desired, existing, action)
+		}
 	}
 
-	// Selectively update only the fields we own to avoid conflicts with other controllers.
-	existing.Spec.Replicas = desired.Spec.Replicas
-	existing.Spec.Template.Spec.Containers[0].Image = desired.Spec.Template.Spec.Containers[0].Image
-	existing.Spec.Template.Spec.Containers[0].Ports = desired.Spec.Template.Spec.Containers[0].Ports
-	log.Info("updating deployment", "name", webapp.Name)
-	return r.Update(ctx, existing)
+	if err := r.applyDeployment(ctx, webapp, spec.DriftPolicy, desired, existing, spec.Autoscaling != nil); err != nil {
+		return err
+	}
+	return r.finishRollout(ctx, webapp, strategy, templateHash)
 }
 
-// reconcileStorage creates or updates the PersistentVolumeClaim for the given WebApp.
-// It sets an owner reference so the PVC is garbage-collected with the WebApp.
-func (r *WebAppReconciler) reconcileStorage(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+// reconcileAutoscaler applies or deletes the HorizontalPodAutoscaler for the
+// given WebApp. It sets an owner reference so the HPA is garbage-collected with the WebApp.
+// When Spec.Autoscaling is removed, the HPA is deleted and the Deployment goes back to
+// Spec.Replicas on the next Deployment update.
+func (r *WebAppReconciler) reconcileAutoscaler(ctx context.Context, webapp *appv1alpha1.WebApp) error {
 	log := logf.FromContext(ctx)
 
-	// Storage is optional — if not specified, nothing to reconcile.
-	if webapp.Spec.Storage == nil {
+	existing := &autoscalingv2.HorizontalPodAutoscaler{}
+	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting autoscaler: %w", err)
+	}
+	found := err == nil
+
+	// Autoscaling is optional — if not specified, remove any autoscaler we created earlier.
+	if webapp.Spec.Autoscaling == nil {
+		if !found || !metav1.IsControlledBy(existing, webapp) {
+			return nil
+		}
+		log.Info("deleting autoscaler", "name", webapp.Name)
+		if err := r.Delete(ctx, existing); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		r.recordEvent(webapp, corev1.EventTypeNormal, "Deleted", "deleted autoscaler %s", webapp.Name)
 		return nil
 	}
 
-	desiredPVC := &corev1.PersistentVolumeClaim{
+	spec := webapp.Spec.DeepCopy()
+	spec.ApplyDefaults()
+
+	desired := &autoscalingv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      webapp.Name + "-pvc", // Naming convention
+			Name:      webapp.Name,
 			Namespace: webapp.Namespace,
 		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			// Access mode is currently hard coded to ReadWriteOnce, might want to make this configurable?
-			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-			Resources: corev1.VolumeResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: webapp.Spec.Storage.Size,
-				},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       webapp.Name,
 			},
+			MinReplicas: spec.Autoscaling.MinReplicas,
+			MaxReplicas: spec.Autoscaling.MaxReplicas,
+			Metrics:     metricsForAutoscaling(spec.Autoscaling),
 		},
 	}
-	if webapp.Spec.Storage.StorageClassName != nil {
-		desiredPVC.Spec.StorageClassName = webapp.Spec.Storage.StorageClassName
-	}
 
-	// Set the WebApp as the owner of the PVC so it is garbage-collected on deletion.
-	if err := controllerutil.SetControllerReference(webapp, desiredPVC, r.Scheme); err != nil {
-		return fmt.Errorf("setting owner reference on PVC: %w", err)
-	}
+	// Only the target, bounds and metrics are applied; behavior is left to the user.
+	return r.apply(ctx, webapp, desired, "autoscaler")
+}
 
-	// Check if PVC already exists; if so, do nothing.
-	// PVC spec (size, storageClassName) is immutable after creation — Kubernetes will
-	// reject updates to these fields unless the StorageClass supports volume expansion.
-	// Resizing is intentionally out of scope for this operator.
-	existing := &corev1.PersistentVolumeClaim{}
-	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name + "-pvc", Namespace: webapp.Namespace}, existing)
-	if apierrors.IsNotFound(err) {
-		log.Info("creating pvc", "name", webapp.Name+"-pvc")
-		return r.Create(ctx, desiredPVC)
-	}
-	if err != nil {
-		return fmt.Errorf("getting pvc: %w", err)
+// reconcileDisruptionBudget applies or deletes the PodDisruptionBudget for the
+// given WebApp. It sets an owner reference so the PDB is garbage-collected with the WebApp.
+// The budget follows the Deployment's current replica count rather than the spec, so it
+// also tracks an autoscaler: it exists only while more than one replica is wanted.
+func (r *WebAppReconciler) reconcileDisruptionBudget(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	log := logf.FromContext(ctx)
+	key := types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}
+
+	dep := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, dep); err != nil {
+		return fmt.Errorf("getting deployment: %w", err)
 	}
 
-	// PVC already exists — no update needed.
-	log.Info("pvc already exists, skipping update", "name", webapp.Name+"-pvc")
-	return nil
-}
+	existing := &policyv1.PodDisruptionBudget{}
+	err := r.Get(ctx, key, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting pod disruption budget: %w", err)
+	}
+	found := err == nil
 
-// reconcileService creates or updates the ClusterIP Service for the given WebApp.
-// It sets an owner reference so the Service is garbage-collected with the WebApp.
-func (r *WebAppReconciler) reconcileService(ctx context.Context, webapp *appv1alpha1.WebApp) error {
-	log := logf.FromContext(ctx)
+	// A budget on a single replica can only block drains, so remove it.
+	if ptr.Deref(dep.Spec.Replicas, 1) <= 1 {
+		if !found || !metav1.IsControlledBy(existing, webapp) {
+			return nil
+		}
+		log.Info("deleting pod disruption budget", "name", webapp.Name)
+		if err := r.Delete(ctx, existing); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		r.recordEvent(webapp, corev1.EventTypeNormal, "Deleted", "deleted pod disruption budget %s", webapp.Name)
+		return nil
+	}
 
-	desired := &corev1.Service{
+	desired := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      webapp.Name,
 			Namespace: webapp.Namespace,
 		},
-		Spec: corev1.ServiceSpec{
-			Selector: labelsForWebApp(webapp.Name),
-			Ports: []corev1.ServicePort{
-				{
-					Port:       webapp.Spec.Port,
-					TargetPort: intstr.FromInt32(webapp.Spec.Port),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
-			Type: corev1.ServiceTypeClusterIP,
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			// Canary pods are left out: they come and go with the rollout steps.
+			Selector: mainPodSelectorForWebApp(webapp.Name),
 		},
 	}
-
-	// Set the WebApp as the owner of the Service so it is garbage-collected on deletion.
-	if err := controllerutil.SetControllerReference(webapp, desired, r.Scheme); err != nil {
-		return fmt.Errorf("setting owner reference on service: %w", err)
+	if d := webapp.Spec.Disruption; d != nil {
+		desired.Spec.MinAvailable = d.MinAvailable
+		desired.Spec.MaxUnavailable = d.MaxUnavailable
+	} else {
+		// Default: drains proceed one pod at a time.
+		desired.Spec.MaxUnavailable = ptr.To(intstr.FromInt32(1))
 	}
 
-	existing := &corev1.Service{}
-	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, existing)
-	if apierrors.IsNotFound(err) {
-		log.Info("creating service", "name", webapp.Name)
-		return r.Create(ctx, desired)
+	return r.apply(ctx, webapp, desired, "pod disruption budget")
+}
+
+// metricsForAutoscaling builds the HPA metric list: resource utilization targets for CPU
+// and memory first, followed by any custom metrics. Returns nil when nothing is set, in
+// which case the API server defaults the HPA to 80% average CPU utilization.
+func metricsForAutoscaling(spec *appv1alpha1.AutoscalingSpec) []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+	resourceTarget := func(name corev1.ResourceName, percent int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: ptr.To(percent),
+				},
+			},
+		}
 	}
-	if err != nil {
-		return fmt.Errorf("getting service: %w", err)
+	if spec.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, resourceTarget(corev1.ResourceCPU, *spec.TargetCPUUtilizationPercentage))
 	}
-
-	// Update the port mapping only; ClusterIP and other fields are immutable.
-	existing.Spec.Ports = desired.Spec.Ports
-	existing.Spec.Selector = desired.Spec.Selector
-	log.Info("updating service", "name", webapp.Name)
-	return r.Update(ctx, existing)
+	if spec.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceTarget(corev1.ResourceMemory, *spec.TargetMemoryUtilizationPercentage))
+	}
+	for i := range spec.Metrics {
+		metrics = append(metrics, *spec.Metrics[i].DeepCopy())
+	}
+	return metrics
 }
 
 // cleanupChildResources removes any resources that are not automatically garbage-collected
@@ -361,10 +655,32 @@ func (r *WebAppReconciler) cleanupChildResources(_ context.Context, webapp *appv
 	return nil
 }
 
-// setCondition updates a single status condition on the WebApp and persists it via
-// the status subresource. It uses meta.SetStatusCondition to handle deduplication.
-func (r *WebAppReconciler) setCondition(ctx context.Context, webapp *appv1alpha1.WebApp,
-	condType string, status metav1.ConditionStatus, reason, message string) error {
+// reasonError attaches a specific Degraded condition reason to a reconcile error, for
+// failures that deserve a more precise reason than the generic one of the child resource.
+type reasonError struct {
+	reason string
+	err    error
+}
+
+// Error implements error.
+func (e *reasonError) Error() string { return e.err.Error() }
+
+// Unwrap returns the underlying error.
+func (e *reasonError) Unwrap() error { return e.err }
+
+// degradedReason returns the reason carried by a reasonError in err's chain, or fallback.
+func degradedReason(err error, fallback string) string {
+	var re *reasonError
+	if errors.As(err, &re) {
+		return re.reason
+	}
+	return fallback
+}
+
+// setCondition sets a single status condition on the WebApp in memory. It uses
+// meta.SetStatusCondition, which only moves LastTransitionTime when the status changes.
+// The change is persisted by patchStatus at the end of the reconcile.
+func setCondition(webapp *appv1alpha1.WebApp, condType string, status metav1.ConditionStatus, reason, message string) {
 	meta.SetStatusCondition(&webapp.Status.Conditions, metav1.Condition{
 		Type:               condType,
 		Status:             status,
@@ -372,67 +688,187 @@ func (r *WebAppReconciler) setCondition(ctx context.Context, webapp *appv1alpha1
 		Message:            message,
 		ObservedGeneration: webapp.Generation,
 	})
-	if err := r.Status().Update(ctx, webapp); err != nil {
-		return fmt.Errorf("updating status condition %s: %w", condType, err)
+}
+
+// patchStatus writes the status changes made to webapp since original was copied, as a
+// single merge patch on the status subresource. Nothing is written when the status is
+// unchanged, and a WebApp deleted in the meantime is ignored.
+func (r *WebAppReconciler) patchStatus(ctx context.Context, original, webapp *appv1alpha1.WebApp) error {
+	if equality.Semantic.DeepEqual(original.Status, webapp.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, webapp, client.MergeFrom(original)); err != nil {
+		return client.IgnoreNotFound(fmt.Errorf("patching status: %w", err))
 	}
 	return nil
 }
 
-// volumesForWebApp returns the list of Volumes to attach to the pod spec.
-// Returns nil if no storage is configured, resulting in no volumes on the pod.
-// The volume name "data" is the shared convention used by volumeMountsForWebApp.
-func volumesForWebApp(name string, storage *appv1alpha1.StorageSpec) []corev1.Volume {
-	if storage == nil {
-		return nil
+// initContainersForWebApp returns the init containers to inject into the pod spec: the
+// single InitContainer, then InitContainers, then Sidecars as native sidecars with
+// restartPolicy Always. Returns nil when there are none, which is the common case.
+// The spec is expected to be defaulted already, so the container names are always set.
+func initContainersForWebApp(spec *appv1alpha1.WebAppSpec) []corev1.Container {
+	var out []corev1.Container
+	if c := spec.InitContainer; c != nil {
+		out = append(out, corev1.Container{
+			Name:          c.Name,
+			Image:         c.Image,
+			Command:       c.Command,
+			Args:          c.Args,
+			Env:           c.Env,
+			EnvFrom:       c.EnvFrom,
+			Ports:         c.Ports,
+			RestartPolicy: c.RestartPolicy,
+			Resources:     c.Resources,
+			VolumeMounts:  c.VolumeMounts,
+		})
+	}
+	for i := range spec.InitContainers {
+		out = append(out, containerForSpec(&spec.InitContainers[i]))
+	}
+	for i := range spec.Sidecars {
+		sidecar := containerForSpec(&spec.Sidecars[i])
+		sidecar.RestartPolicy = ptr.To(corev1.ContainerRestartPolicyAlways)
+		out = append(out, sidecar)
+	}
+	return out
+}
+
+// containerForSpec returns the pod container for an entry of InitContainers or Sidecars.
+func containerForSpec(spec *appv1alpha1.ContainerSpec) corev1.Container {
+	return corev1.Container{
+		Name:           spec.Name,
+		Image:          spec.Image,
+		Command:        spec.Command,
+		Args:           spec.Args,
+		Env:            spec.Env,
+		EnvFrom:        spec.EnvFrom,
+		Ports:          spec.Ports,
+		Resources:      spec.Resources,
+		VolumeMounts:   spec.VolumeMounts,
+		LivenessProbe:  spec.LivenessProbe,
+		ReadinessProbe: spec.ReadinessProbe,
+		StartupProbe:   spec.StartupProbe,
+		RestartPolicy:  spec.RestartPolicy,
 	}
-	return []corev1.Volume{
-		{
-			Name: "data",
-			VolumeSource: corev1.VolumeSource{
-				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: name + "-pvc",
+}
+
+// probesForWebApp returns the liveness and readiness probes for the main container.
+// Explicit probes in the spec win; otherwise HealthCheck expands into HTTP GET probes
+// against the application port. Both are nil when neither is configured.
+func probesForWebApp(spec *appv1alpha1.WebAppSpec) (liveness, readiness *corev1.Probe) {
+	liveness, readiness = spec.LivenessProbe, spec.ReadinessProbe
+	if spec.HealthCheck == nil {
+		return liveness, readiness
+	}
+	httpProbe := func() *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: spec.HealthCheck.Path,
+					Port: intstr.FromInt32(spec.Port),
 				},
 			},
-		},
+		}
 	}
+	if liveness == nil {
+		liveness = httpProbe()
+	}
+	if readiness == nil {
+		readiness = httpProbe()
+	}
+	return liveness, readiness
 }
 
-// volumeMountsForWebApp returns the list of VolumeMounts to attach to the main container.
-// Returns nil if no storage is configured, resulting in no mounts on the container.
-// Mounts the PVC at /data — a conventional path for persistent application data.
-func volumeMountsForWebApp(storage *appv1alpha1.StorageSpec) []corev1.VolumeMount {
-	if storage == nil {
+// resourceSummaryForPod computes the effective requests and limits of one pod and the
+// totals across the given replica count, following the same rules the scheduler uses:
+// regular containers and sidecars add up, and each regular init container only needs
+// room next to the sidecars started before it. A limit is reported only for resources
+// that every container limits, since a single unlimited container makes the pod unbounded.
+func resourceSummaryForPod(podSpec *corev1.PodSpec, replicas int32) *appv1alpha1.ResourceSummary {
+	podRequests := effectivePodResources(podSpec, func(c *corev1.Container) corev1.ResourceList {
+		return c.Resources.Requests
+	})
+	podLimits := effectivePodResources(podSpec, func(c *corev1.Container) corev1.ResourceList {
+		return c.Resources.Limits
+	})
+	for _, c := range slices.Concat(podSpec.InitContainers, podSpec.Containers) {
+		for name := range podLimits {
+			if _, ok := c.Resources.Limits[name]; !ok {
+				delete(podLimits, name)
+			}
+		}
+	}
+	if len(podLimits) == 0 {
+		podLimits = nil
+	}
+
+	return &appv1alpha1.ResourceSummary{
+		PodRequests:   podRequests,
+		PodLimits:     podLimits,
+		TotalRequests: scaleResourceList(podRequests, replicas),
+		TotalLimits:   scaleResourceList(podLimits, replicas),
+	}
+}
+
+// effectivePodResources applies the pod resource formula to the list returned by get
+// for every container: max(sum(containers) + sum(sidecars), max over init containers of
+// init + sidecars started before it). Returns nil when no container sets anything.
+func effectivePodResources(podSpec *corev1.PodSpec, get func(*corev1.Container) corev1.ResourceList) corev1.ResourceList {
+	sidecars := corev1.ResourceList{}
+	initPeak := corev1.ResourceList{}
+	for i := range podSpec.InitContainers {
+		c := &podSpec.InitContainers[i]
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResourceList(sidecars, get(c))
+			continue
+		}
+		running := sidecars.DeepCopy()
+		addResourceList(running, get(c))
+		maxResourceList(initPeak, running)
+	}
+
+	pod := sidecars.DeepCopy()
+	for i := range podSpec.Containers {
+		addResourceList(pod, get(&podSpec.Containers[i]))
+	}
+	maxResourceList(pod, initPeak)
+	if len(pod) == 0 {
 		return nil
 	}
-	return []corev1.VolumeMount{
-		{
-			Name:      "data",
-			MountPath: "/data",
-		},
+	return pod
+}
+
+// addResourceList adds every quantity in src to dst.
+func addResourceList(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		sum := dst[name]
+		sum.Add(q)
+		dst[name] = sum
 	}
 }
 
-// initContainersForWebApp returns the list of init containers to inject into the
-// pod spec. Returns nil (no init containers) if spec is nil, which is the common case.
-// Defaults the container name to "init" if not specified by the user.
-func initContainersForWebApp(spec *appv1alpha1.InitContainerSpec) []corev1.Container {
-	if spec == nil {
+// maxResourceList raises every quantity in dst to the value in src where src is larger.
+func maxResourceList(dst, src corev1.ResourceList) {
+	for name, q := range src {
+		if cur, ok := dst[name]; !ok || q.Cmp(cur) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
+
+// scaleResourceList returns a copy of list with every quantity multiplied by n.
+func scaleResourceList(list corev1.ResourceList, n int32) corev1.ResourceList {
+	if len(list) == 0 {
 		return nil
 	}
-	name := spec.Name
-	if name == "" {
-		name = "init"
-	}
-	return []corev1.Container{
-		{
-			Name:          name,
-			Image:         spec.Image,
-			Command:       spec.Command,
-			Args:          spec.Args,
-			Env:           spec.Env,
-			RestartPolicy: spec.RestartPolicy,
-		},
+	out := make(corev1.ResourceList, len(list))
+	for name, q := range list {
+		scaled := q.DeepCopy()
+		scaled.Mul(int64(n))
+		out[name] = scaled
 	}
+	return out
 }
 
 // labelsForWebApp returns the standard label set applied to all resources
@@ -447,13 +883,43 @@ func labelsForWebApp(name string) map[string]string {
 
 // SetupWithManager sets up the controller with the Manager.
 // It watches WebApp resources and also watches owned Deployments and Services
-// so that changes to child resources trigger reconciliation.
+// so that changes to child resources trigger reconciliation. ConfigMaps and Secrets
+// are watched through field indexes on the WebApps that reference them, so a
+// configuration change re-hashes the pod template and rolls the pods. Only the metadata
+// of Secrets is cached; a data change still bumps their resourceVersion. HTTPRoutes are
+// only watched when the Gateway API is installed at startup; on clusters that add it
+// later they are still reconciled, just without a watch until the operator restarts.
 func (r *WebAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	ctx := context.Background()
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &appv1alpha1.WebApp{}, configMapRefIndex,
+		configMapRefsIndexer); err != nil {
+		return fmt.Errorf("indexing webapps by configmap: %w", err)
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &appv1alpha1.WebApp{}, secretRefIndex,
+		secretRefsIndexer); err != nil {
+		return fmt.Errorf("indexing webapps by secret: %w", err)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&appv1alpha1.WebApp{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Named("webapp").
-		Complete(r)
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.Ingress{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.webAppsForConfigMap)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.webAppsForSecret), builder.OnlyMetadata)
+
+	routeAPI, err := hasHTTPRouteAPI(mgr.GetRESTMapper())
+	if err != nil {
+		return err
+	}
+	if routeAPI {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(httpRouteGVK)
+		b = b.Owns(route)
+	}
+
+	return b.Named("webapp").Complete(r)
 }
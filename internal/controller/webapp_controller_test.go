@@ -18,11 +18,33 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -82,3 +104,1817 @@ var _ = Describe("WebApp Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("WebApp status", func() {
+	const resourceName = "status-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should report the processed generation with every condition in one write", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec:       appv1alpha1.WebAppSpec{Image: "nginx:1.25"},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeProgressing)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(webapp.Status.Conditions, appv1alpha1.TypeAvailable)).To(BeTrue())
+
+		By("completing the rollout, which envtest has no deployment controller for")
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		dep.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: dep.Generation,
+			Replicas:           1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1,
+		}
+		Expect(k8sClient.Status().Update(ctx, dep)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.ObservedGeneration).To(Equal(webapp.Generation))
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeAvailable)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(webapp.Status.Conditions, appv1alpha1.TypeProgressing)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(webapp.Status.Conditions, appv1alpha1.TypeDegraded)).To(BeTrue())
+		resourceVersion := webapp.ResourceVersion
+
+		By("leaving an unchanged status alone")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.ResourceVersion).To(Equal(resourceVersion))
+
+		By("trailing the generation until the new spec is reconciled")
+		webapp.Spec.Image = "nginx:1.26"
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		Expect(webapp.Status.ObservedGeneration).To(BeNumerically("<", webapp.Generation))
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.ObservedGeneration).To(Equal(webapp.Generation))
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeProgressing)).To(BeTrue())
+	})
+})
+
+var _ = Describe("WebApp config-triggered rollouts", func() {
+	const resourceName = "config-hash-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		reconciler *WebAppReconciler
+		configMap  *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		reconciler = &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName + "-config", Namespace: "default"},
+			Data:       map[string]string{"LOG_LEVEL": "info"},
+		}
+		Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image: "nginx:1.25",
+				EnvFrom: []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+					},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+	})
+
+	// reconcileAndReadHash reconciles twice (finalizer, then children) and returns the
+	// config hash annotation from the Deployment's pod template.
+	reconcileAndReadHash := func() string {
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		return dep.Spec.Template.Annotations[configHashAnnotation]
+	}
+
+	It("should change the pod template hash when referenced data changes", func() {
+		before := reconcileAndReadHash()
+		Expect(before).NotTo(BeEmpty())
+
+		configMap.Data["LOG_LEVEL"] = "debug"
+		Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+		Expect(reconcileAndReadHash()).NotTo(Equal(before))
+	})
+})
+
+var _ = Describe("WebApp autoscaling", func() {
+	const resourceName = "autoscaling-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should create an HPA and leave the Deployment replica count to it", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image: "nginx:1.25",
+				Autoscaling: &appv1alpha1.AutoscalingSpec{
+					MinReplicas:                    ptr.To[int32](2),
+					MaxReplicas:                    5,
+					TargetCPUUtilizationPercentage: ptr.To[int32](70),
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		Expect(k8sClient.Get(ctx, key, hpa)).To(Succeed())
+		Expect(hpa.Spec.MinReplicas).To(HaveValue(Equal(int32(2))))
+		Expect(hpa.Spec.MaxReplicas).To(Equal(int32(5)))
+		Expect(hpa.Spec.Metrics).To(HaveLen(1))
+		Expect(hpa.Spec.ScaleTargetRef.Name).To(Equal(resourceName))
+
+		By("simulating the autoscaler scaling the Deployment out")
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Replicas).To(HaveValue(Equal(int32(2))))
+		dep.Spec.Replicas = ptr.To[int32](4)
+		Expect(k8sClient.Update(ctx, dep)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Replicas).To(HaveValue(Equal(int32(4))))
+
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.Autoscaling).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("WebApp server-side apply", func() {
+	const resourceName = "field-conflict-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should take back fields changed by another manager and report the conflict", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec:       appv1alpha1.WebAppSpec{Image: "nginx:1.25"},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.ManagedFields).To(ContainElement(HaveField("Manager", fieldManager)))
+
+		By("changing the image and adding an annotation as another field manager")
+		dep.Spec.Template.Spec.Containers[0].Image = "nginx:edited"
+		dep.Annotations = map[string]string{"example.com/owner": "sre"}
+		Expect(k8sClient.Update(ctx, dep, client.FieldOwner("kubectl-edit"))).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25"))
+		Expect(dep.Annotations).To(HaveKeyWithValue("example.com/owner", "sre"))
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeFieldConflict)).To(BeTrue())
+
+		By("clearing the condition once nobody else claims the fields")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(webapp.Status.Conditions, appv1alpha1.TypeFieldConflict)).To(BeTrue())
+	})
+})
+
+var _ = Describe("WebApp drift policy", func() {
+	const resourceName = "drift-policy-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should report a manual edit under Warn and revert it under Enforce", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:       "nginx:1.25",
+				DriftPolicy: appv1alpha1.DriftPolicyWarn,
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("adding an env var by hand")
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		dep.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "DEBUG", Value: "1"}}
+		Expect(k8sClient.Update(ctx, dep, client.FieldOwner("kubectl-edit"))).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Env).To(HaveLen(1))
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		drifted := meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeDrifted)
+		Expect(drifted).NotTo(BeNil())
+		Expect(drifted.Reason).To(Equal("DriftDetected"))
+		Expect(drifted.Message).To(ContainSubstring("spec.template.spec.containers[webapp].env"))
+
+		By("switching to Enforce")
+		webapp.Spec.DriftPolicy = appv1alpha1.DriftPolicyEnforce
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeDrifted).Reason).
+			To(Equal("DriftCorrected"))
+	})
+})
+
+var _ = Describe("WebApp rollout strategies", func() {
+	ctx := context.Background()
+
+	// completeRollout stands in for the deployment controller, which envtest does not run.
+	completeRollout := func(key types.NamespacedName) {
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		replicas := ptr.Deref(dep.Spec.Replicas, 1)
+		dep.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: dep.Generation,
+			Replicas:           replicas, UpdatedReplicas: replicas, ReadyReplicas: replicas, AvailableReplicas: replicas,
+		}
+		Expect(k8sClient.Status().Update(ctx, dep)).To(Succeed())
+	}
+
+	It("should shift replicas to a canary step by step and promote it on request", func() {
+		key := types.NamespacedName{Name: "canary-test", Namespace: "default"}
+		canaryKey := types.NamespacedName{Name: "canary-test-canary", Namespace: "default"}
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:    "nginx:1.25",
+				Replicas: ptr.To[int32](4),
+				Rollout: &appv1alpha1.RolloutSpec{
+					Strategy: appv1alpha1.RolloutStrategyCanary,
+					Steps:    []appv1alpha1.CanaryStep{{Weight: 25}},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("changing the image")
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		webapp.Spec.Image = "nginx:1.26"
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		dep, canary := &appsv1.Deployment{}, &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25"))
+		Expect(*dep.Spec.Replicas).To(Equal(int32(3)))
+		Expect(k8sClient.Get(ctx, canaryKey, canary)).To(Succeed())
+		Expect(canary.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.26"))
+		Expect(*canary.Spec.Replicas).To(Equal(int32(1)))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("app.kubernetes.io/instance", key.Name))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue(rolloutTrackLabel, trackCanary))
+		Expect(dep.Spec.Selector.MatchLabels).To(Equal(labelsForWebApp(key.Name)))
+
+		By("pausing at the step once the canary is available")
+		completeRollout(canaryKey)
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.Rollout.Phase).To(Equal(appv1alpha1.RolloutPhasePaused))
+		Expect(webapp.Status.Rollout.Weight).To(Equal(int32(25)))
+
+		By("promoting the rollout")
+		webapp.Annotations = map[string]string{appv1alpha1.RolloutActionAnnotation: appv1alpha1.RolloutActionPromote}
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Annotations).NotTo(HaveKey(appv1alpha1.RolloutActionAnnotation))
+		Expect(webapp.Status.Rollout.Phase).To(Equal(appv1alpha1.RolloutPhasePromoting))
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.26"))
+		Expect(*dep.Spec.Replicas).To(Equal(int32(4)))
+
+		By("removing the canary once the main deployment is rolled out")
+		completeRollout(key)
+		for range 2 {
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.Rollout.Phase).To(Equal(appv1alpha1.RolloutPhaseCompleted))
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, canaryKey, canary))).To(BeTrue())
+	})
+
+	It("should keep the main service on the old pods until a BlueGreen preview is promoted", func() {
+		key := types.NamespacedName{Name: "bluegreen-test", Namespace: "default"}
+		previewKey := types.NamespacedName{Name: "bluegreen-test-preview", Namespace: "default"}
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:   "nginx:1.25",
+				Rollout: &appv1alpha1.RolloutSpec{Strategy: appv1alpha1.RolloutStrategyBlueGreen},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		webapp.Spec.Image = "nginx:1.26"
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		svc, previewSvc := &corev1.Service{}, &corev1.Service{}
+		Expect(k8sClient.Get(ctx, key, svc)).To(Succeed())
+		Expect(svc.Spec.Selector).To(Equal(labelsForWebApp(key.Name)))
+		Expect(k8sClient.Get(ctx, previewKey, previewSvc)).To(Succeed())
+		Expect(previewSvc.Spec.Selector).To(Equal(previewLabelsForWebApp(key.Name)))
+
+		By("switching the main service to the preview once promoted")
+		completeRollout(previewKey)
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		webapp.Annotations = map[string]string{appv1alpha1.RolloutActionAnnotation: appv1alpha1.RolloutActionPromote}
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, svc)).To(Succeed())
+		Expect(svc.Spec.Selector).To(Equal(previewLabelsForWebApp(key.Name)))
+
+		By("switching back once the main deployment runs the new pod template")
+		completeRollout(key)
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, svc)).To(Succeed())
+		Expect(svc.Spec.Selector).To(Equal(labelsForWebApp(key.Name)))
+	})
+})
+
+var _ = Describe("endRolloutStep", func() {
+	now := time.Now()
+
+	It("should pause once the replicas are available and end the step after the pause", func() {
+		st := &appv1alpha1.RolloutStatus{Phase: appv1alpha1.RolloutPhaseProgressing}
+		pause := &metav1.Duration{Duration: time.Minute}
+
+		Expect(endRolloutStep(st, false, pause, false, now)).To(BeFalse())
+		Expect(st.Phase).To(Equal(appv1alpha1.RolloutPhaseProgressing))
+		Expect(endRolloutStep(st, true, pause, false, now)).To(BeFalse())
+		Expect(st.Phase).To(Equal(appv1alpha1.RolloutPhasePaused))
+		Expect(endRolloutStep(st, true, pause, false, now.Add(30*time.Second))).To(BeFalse())
+		Expect(endRolloutStep(st, true, pause, false, now.Add(time.Minute))).To(BeTrue())
+	})
+
+	It("should wait for a promotion without a pause", func() {
+		st := &appv1alpha1.RolloutStatus{Phase: appv1alpha1.RolloutPhaseProgressing}
+		Expect(endRolloutStep(st, true, nil, false, now.Add(time.Hour))).To(BeFalse())
+		Expect(st.Message).To(ContainSubstring(appv1alpha1.RolloutActionAnnotation))
+		Expect(endRolloutStep(st, false, nil, true, now)).To(BeTrue())
+	})
+
+	It("should size canaries by weight with at least one replica on each track", func() {
+		split := func(total, weight int32) []int32 {
+			canary, stable := canaryStepReplicas(total, weight)
+			return []int32{canary, stable}
+		}
+		Expect(split(4, 25)).To(Equal([]int32{1, 3}))
+		Expect(split(10, 25)).To(Equal([]int32{3, 7}))
+		Expect(split(3, 100)).To(Equal([]int32{3, 1}))
+		Expect(split(1, 10)).To(Equal([]int32{1, 1}))
+		Expect(split(1, 100)).To(Equal([]int32{1, 1}))
+		Expect(split(2, 10)).To(Equal([]int32{1, 1}))
+		Expect(split(2, 50)).To(Equal([]int32{1, 1}))
+		Expect(split(2, 100)).To(Equal([]int32{2, 1}))
+		Expect(split(0, 50)).To(Equal([]int32{1, 0}))
+	})
+
+	It("should leave canary pods out of the main pod selector", func() {
+		mainPods, err := metav1.LabelSelectorAsSelector(mainPodSelectorForWebApp("shop"))
+		Expect(err).NotTo(HaveOccurred())
+		canary := canaryLabelsForWebApp("shop")
+		Expect(mainPods.Matches(labels.Set(labelsForWebApp("shop")))).To(BeTrue())
+		Expect(mainPods.Matches(labels.Set(canary))).To(BeFalse())
+		Expect(labels.SelectorFromSet(canary).Matches(labels.Set(labelsForWebApp("shop")))).To(BeFalse())
+
+		desired := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labelsForWebApp("shop")},
+				Spec: corev1.PodSpec{TopologySpreadConstraints: topologySpreadConstraintsForWebApp("shop",
+					&appv1alpha1.WebAppSpec{Spread: appv1alpha1.SpreadZone})},
+			}},
+		}
+		dep := rolloutDeployment(desired, canaryName("shop"), canary, 1)
+		Expect(dep.Spec.Selector.MatchLabels).To(Equal(canary))
+		for _, c := range dep.Spec.Template.Spec.TopologySpreadConstraints {
+			Expect(c.LabelSelector).To(Equal(&metav1.LabelSelector{MatchLabels: canary}))
+		}
+		Expect(desired.Spec.Template.Spec.TopologySpreadConstraints[0].LabelSelector).
+			To(Equal(mainPodSelectorForWebApp("shop")))
+	})
+})
+
+var _ = Describe("WebApp schedules", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "schedule-test", Namespace: "default"}
+
+	It("should scale to the replica count of the schedule that fired last", func() {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		Expect(err).NotTo(HaveOccurred())
+		// Wednesday evening, after the night schedule fired.
+		clock := testingclock.NewFakePassiveClock(time.Date(2025, time.January, 15, 21, 0, 0, 0, berlin))
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Clock: clock}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:    "nginx:1.25",
+				Replicas: ptr.To[int32](3),
+				Schedules: []appv1alpha1.ReplicaSchedule{
+					{Name: "night", Cron: "0 20 * * MON-FRI", TimeZone: "Europe/Berlin", Replicas: 0},
+					{Name: "day", Cron: "0 7 * * MON-FRI", TimeZone: "Europe/Berlin", Replicas: 2},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() reconcile.Result {
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+			return result
+		}
+		reconcileOnce()
+		reconcileOnce()
+
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(*dep.Spec.Replicas).To(BeZero())
+		Expect(webapp.Status.Schedule.Active).To(Equal("night"))
+		Expect(webapp.Status.Schedule.Replicas).To(HaveValue(BeZero()))
+		Expect(webapp.Status.Schedule.LastTriggerTime.Time).
+			To(BeTemporally("==", time.Date(2025, time.January, 15, 20, 0, 0, 0, berlin)))
+		Expect(webapp.Status.Schedule.Next).To(Equal("day"))
+		Expect(webapp.Status.Schedule.NextTriggerTime.Time).
+			To(BeTemporally("==", time.Date(2025, time.January, 16, 7, 0, 0, 0, berlin)))
+
+		By("requeueing at the next schedule when it comes before the periodic resync")
+		clock.SetTime(time.Date(2025, time.January, 16, 6, 59, 30, 0, berlin))
+		Expect(reconcileOnce().RequeueAfter).To(Equal(30 * time.Second))
+
+		By("scaling up once the day schedule fires")
+		clock.SetTime(time.Date(2025, time.January, 16, 7, 0, 0, 0, berlin))
+		Expect(reconcileOnce().RequeueAfter).To(Equal(requeueAfter))
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(*dep.Spec.Replicas).To(Equal(int32(2)))
+		Expect(webapp.Status.Schedule.Active).To(Equal("day"))
+		Expect(webapp.Status.Schedule.Next).To(Equal("night"))
+	})
+})
+
+var _ = Describe("scheduleStatusForWebApp", func() {
+	now := time.Date(2025, time.January, 18, 12, 0, 0, 0, time.UTC) // a Saturday
+
+	It("should keep the weekday schedule that fired last over the weekend", func() {
+		status, err := scheduleStatusForWebApp([]appv1alpha1.ReplicaSchedule{
+			{Name: "night", Cron: "0 20 * * MON-FRI", Replicas: 0},
+			{Name: "day", Cron: "0 7 * * MON-FRI", Replicas: 2},
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Active).To(Equal("night"))
+		Expect(status.LastTriggerTime.Time).To(BeTemporally("==", time.Date(2025, time.January, 17, 20, 0, 0, 0, time.UTC)))
+		Expect(status.Next).To(Equal("day"))
+		Expect(status.NextTriggerTime.Time).To(BeTemporally("==", time.Date(2025, time.January, 20, 7, 0, 0, 0, time.UTC)))
+	})
+
+	It("should let the later schedule in the list win a tie and compare across time zones", func() {
+		status, err := scheduleStatusForWebApp([]appv1alpha1.ReplicaSchedule{
+			{Name: "utc", Cron: "0 11 * * *", Replicas: 1},
+			{Name: "berlin", Cron: "0 12 * * *", TimeZone: "Europe/Berlin", Replicas: 4},
+			{Name: "last", Cron: "0 11 * * *", Replicas: 5},
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Active).To(Equal("last"))
+		Expect(status.Replicas).To(HaveValue(Equal(int32(5))))
+	})
+
+	It("should find the last firing of rare schedules and across daylight saving time", func() {
+		status, err := scheduleStatusForWebApp([]appv1alpha1.ReplicaSchedule{
+			{Name: "leap", Cron: "30 6 29 2 *", Replicas: 3},
+		}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.LastTriggerTime.Time).To(BeTemporally("==", time.Date(2024, time.February, 29, 6, 30, 0, 0, time.UTC)))
+		Expect(status.NextTriggerTime.Time).To(BeTemporally("==", time.Date(2028, time.February, 29, 6, 30, 0, 0, time.UTC)))
+
+		// Berlin moved from UTC+1 to UTC+2 on 30 March 2025.
+		status, err = scheduleStatusForWebApp([]appv1alpha1.ReplicaSchedule{
+			{Name: "day", Cron: "0 7 * * *", TimeZone: "Europe/Berlin", Replicas: 2},
+		}, time.Date(2025, time.March, 30, 12, 0, 0, 0, time.UTC))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.LastTriggerTime.Time).To(BeTemporally("==", time.Date(2025, time.March, 30, 5, 0, 0, 0, time.UTC)))
+		Expect(status.NextTriggerTime.Time).To(BeTemporally("==", time.Date(2025, time.March, 31, 5, 0, 0, 0, time.UTC)))
+	})
+
+	It("should report an invalid schedule with its own reason", func() {
+		_, err := scheduleStatusForWebApp([]appv1alpha1.ReplicaSchedule{{Name: "bad", Cron: "every day"}}, now)
+		Expect(degradedReason(err, "ScheduleFailed")).To(Equal("InvalidSchedule"))
+		Expect(err).To(MatchError(ContainSubstring("schedule bad")))
+	})
+})
+
+var _ = Describe("WebApp suspend", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "suspend-test", Namespace: "default"}
+
+	It("should freeze the child resources and scale to zero until resumed", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       appv1alpha1.WebAppSpec{Image: "nginx:1.25", Replicas: ptr.To[int32](3)},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		}
+		reconcileOnce()
+		reconcileOnce()
+
+		By("suspending and changing the image")
+		webapp.Spec.Suspend = &appv1alpha1.SuspendSpec{}
+		webapp.Spec.Image = "nginx:1.26"
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25"))
+		cond := meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeSuspended)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("Suspended"))
+		Expect(webapp.Status.ObservedGeneration).To(Equal(webapp.Generation))
+
+		By("scaling to zero")
+		webapp.Spec.Suspend.ScaleToZero = true
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(*dep.Spec.Replicas).To(BeZero())
+		Expect(dep.Annotations).To(HaveKeyWithValue(suspendedReplicasAnnotation, "3"))
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25"))
+		Expect(meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeSuspended).Reason).
+			To(Equal("ScaledToZero"))
+		Expect(meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeAvailable).Reason).
+			To(Equal("ScaledToZero"))
+
+		By("resuming")
+		webapp.Spec.Suspend = nil
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(*dep.Spec.Replicas).To(Equal(int32(3)))
+		Expect(dep.Annotations).NotTo(HaveKey(suspendedReplicasAnnotation))
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.26"))
+		Expect(meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeSuspended)).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeDrifted)).To(BeFalse())
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeFieldConflict)).To(BeFalse())
+	})
+})
+
+// staticResolver resolves every image to the same digest, or fails with err. It records
+// the credentials of the last call.
+type staticResolver struct {
+	digest      string
+	err         error
+	calls       int
+	credentials []RegistryCredential
+}
+
+// Resolve implements ImageResolver.
+func (s *staticResolver) Resolve(_ context.Context, _ string, credentials []RegistryCredential) (string, error) {
+	s.calls++
+	s.credentials = credentials
+	return s.digest, s.err
+}
+
+var _ = Describe("WebApp image pinning", func() {
+	const (
+		resourceName = "image-pin-test"
+		digest1      = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		digest2      = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should run the resolved digest and keep it while the registry is unreachable", func() {
+		resolver := &staticResolver{digest: digest1}
+		reconciler := &WebAppReconciler{
+			Client: k8sClient, Scheme: k8sClient.Scheme(),
+			ImageResolver: resolver, ImageResolveInterval: time.Hour,
+		}
+		pullSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName + "-pull", Namespace: "default"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(
+				`{"auths":{"docker.io":{"username":"ci","password":"s3cret"}}}`)},
+		}
+		Expect(k8sClient.Create(ctx, pullSecret)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, pullSecret)).To(Succeed())
+		})
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:       "nginx:1.25",
+				ImagePolicy: appv1alpha1.ImagePolicyPinDigest,
+				ImagePullSecrets: []corev1.LocalObjectReference{
+					{Name: pullSecret.Name}, {Name: "missing-pull-secret"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25@" + digest1))
+		Expect(dep.Spec.Template.Spec.ImagePullSecrets).To(Equal(webapp.Spec.ImagePullSecrets))
+		Expect(resolver.credentials).To(Equal([]RegistryCredential{{Username: "ci", Password: "s3cret"}}))
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.Image.Tag).To(Equal("nginx:1.25"))
+		Expect(webapp.Status.Image.Digest).To(Equal(digest1))
+
+		By("not asking the registry again within the interval")
+		resolver.digest = digest2
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolver.calls).To(Equal(1))
+
+		By("keeping the digest when re-resolving fails")
+		reconciler.ImageResolveInterval = time.Nanosecond
+		resolver.err = fmt.Errorf("registry unavailable")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25@" + digest1))
+
+		By("rolling out the new digest once the tag moved")
+		resolver.err = nil
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.25@" + digest2))
+	})
+})
+
+var _ = Describe("registryResolver", func() {
+	const digest = "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	It("should resolve a tag through an anonymous bearer token", func() {
+		// The server stands in for a registry:2 instance behind a token service.
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			switch {
+			case req.URL.Path == "/token":
+				Expect(req.URL.Query().Get("scope")).To(Equal("repository:team/shop:pull"))
+				_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+			case req.Header.Get("Authorization") != "Bearer anonymous":
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+			case req.Method == http.MethodHead && req.URL.Path == "/v2/team/shop/manifests/1.0":
+				Expect(req.Header.Get("Accept")).To(ContainSubstring("application/vnd.oci.image.index.v1+json"))
+				w.Header().Set("Docker-Content-Digest", digest)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		DeferCleanup(server.Close)
+		host := strings.TrimPrefix(server.URL, "http://")
+
+		resolver := &registryResolver{client: server.Client()}
+		Expect(resolver.Resolve(context.Background(), host+"/team/shop:1.0", nil)).To(Equal(digest))
+		_, err := resolver.Resolve(context.Background(), host+"/team/shop:2.0", nil)
+		Expect(err).To(MatchError(ContainSubstring("404")))
+	})
+
+	It("should sign in with the first credential the registry accepts", func() {
+		// The token service only issues tokens to ci, the way a private registry does.
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			user, password, ok := req.BasicAuth()
+			switch {
+			case req.URL.Path == "/token" && ok && user == "ci" && password == "s3cret":
+				_, _ = w.Write([]byte(`{"token":"private"}`))
+			case req.URL.Path == "/token":
+				w.WriteHeader(http.StatusUnauthorized)
+			case req.Header.Get("Authorization") != "Bearer private":
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+			default:
+				w.Header().Set("Docker-Content-Digest", digest)
+			}
+		}))
+		DeferCleanup(server.Close)
+		image := strings.TrimPrefix(server.URL, "http://") + "/team/shop:1.0"
+
+		resolver := &registryResolver{client: server.Client()}
+		_, err := resolver.Resolve(context.Background(), image, nil)
+		Expect(err).To(MatchError(ContainSubstring("401")))
+		Expect(resolver.Resolve(context.Background(), image, []RegistryCredential{
+			{Username: "ci", Password: "wrong"},
+			{Username: "ci", Password: "s3cret"},
+		})).To(Equal(digest))
+	})
+
+	It("should answer a Basic challenge with the credential", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if user, password, ok := req.BasicAuth(); !ok || user != "ci" || password != "s3cret" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		}))
+		DeferCleanup(server.Close)
+		image := strings.TrimPrefix(server.URL, "http://") + "/shop:1.0"
+
+		resolver := &registryResolver{client: server.Client()}
+		_, err := resolver.Resolve(context.Background(), image, nil)
+		Expect(err).To(MatchError(ContainSubstring("requires credentials")))
+		Expect(resolver.Resolve(context.Background(), image,
+			[]RegistryCredential{{Username: "ci", Password: "s3cret"}})).To(Equal(digest))
+	})
+
+	It("should read the credentials of a registry from docker config secrets", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "pull"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{
+				"https://index.docker.io/v1/":{"auth":"aHViOmh1Yi1wYXNz"},
+				"*.registry.example.com":{"username":"ci","password":"s3cret"},
+				"ghcr.io":{"username":"other","password":"other"}}}`)},
+		}
+		Expect(registryCredentialsFromSecret(secret, "registry-1.docker.io")).To(Equal(
+			[]RegistryCredential{{Username: "hub", Password: "hub-pass"}}))
+		Expect(registryCredentialsFromSecret(secret, "eu.registry.example.com")).To(Equal(
+			[]RegistryCredential{{Username: "ci", Password: "s3cret"}}))
+		Expect(registryCredentialsFromSecret(secret, "registry.example.com")).To(BeEmpty())
+		Expect(registryCredentialsFromSecret(secret, "a.b.registry.example.com")).To(BeEmpty())
+
+		legacy := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy"},
+			Type:       corev1.SecretTypeDockercfg,
+			Data: map[string][]byte{corev1.DockerConfigKey: []byte(
+				`{"localhost:5000":{"username":"dev","password":"dev"}}`)},
+		}
+		Expect(registryCredentialsFromSecret(legacy, "localhost:5000")).To(Equal(
+			[]RegistryCredential{{Username: "dev", Password: "dev"}}))
+
+		opaque := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "opaque"}, Type: corev1.SecretTypeOpaque}
+		Expect(registryCredentialsFromSecret(opaque, "localhost:5000")).To(BeEmpty())
+	})
+
+	It("should split references the way the kubelet does", func() {
+		Expect(parseImageReference("nginx")).To(Equal(imageReference{
+			host: "registry-1.docker.io", repository: "library/nginx", tag: "latest",
+		}))
+		Expect(parseImageReference("docker.io/bitnami/redis:7.2")).To(Equal(imageReference{
+			host: "registry-1.docker.io", repository: "bitnami/redis", tag: "7.2",
+		}))
+		Expect(parseImageReference("localhost:5000/shop:1.0")).To(Equal(imageReference{
+			host: "localhost:5000", repository: "shop", tag: "1.0",
+		}))
+		Expect(imageReference{host: "localhost:5000"}.scheme()).To(Equal("http"))
+		Expect(imageReference{host: "ghcr.io"}.scheme()).To(Equal("https"))
+		Expect(pinnedImage("nginx:1.25@sha256:old", digest)).To(Equal("nginx:1.25@" + digest))
+	})
+})
+
+var _ = Describe("deploymentDrift", func() {
+	desired := &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  appv1alpha1.MainContainerName,
+						Image: "nginx:1.25",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.5")},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	It("should not treat server defaults and foreign annotations as drift", func() {
+		live := desired.DeepCopy()
+		live.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+		live.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		live.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+		live.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("500m")
+
+		Expect(deploymentDrift(desired, live, true)).To(BeEmpty())
+	})
+
+	It("should list changed fields and added list items", func() {
+		live := desired.DeepCopy()
+		live.Spec.Replicas = ptr.To[int32](5)
+		live.Spec.Template.Spec.Containers[0].Image = "nginx:edited"
+		live.Spec.Template.Spec.Containers = append(live.Spec.Template.Spec.Containers,
+			corev1.Container{Name: "debug", Image: "busybox"})
+
+		Expect(deploymentDrift(desired, live, true)).To(Equal([]string{
+			"spec.replicas", "spec.template.spec.containers",
+		}))
+		live.Spec.Template.Spec.Containers = live.Spec.Template.Spec.Containers[:1]
+		Expect(deploymentDrift(desired, live, false)).To(Equal([]string{
+			"spec.template.spec.containers[webapp].image",
+		}))
+	})
+
+	It("should remove only the list items added by other managers", func() {
+		desired := desired.DeepCopy()
+		desired.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}
+		live := desired.DeepCopy()
+		live.Spec.Template.Spec.Containers[0].Env = append(live.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: "DEBUG", Value: "1"})
+		live.Spec.Template.Spec.Containers = append(live.Spec.Template.Spec.Containers,
+			corev1.Container{Name: "debug", Image: "busybox"}, corev1.Container{Name: "shell", Image: "busybox"})
+
+		ops, err := staleListItems(desired, live)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ops).To(HaveLen(6))
+		Expect(ops[1]).To(Equal(jsonPatchOperation{Op: "remove", Path: "/spec/template/spec/containers/0/env/1"}))
+		Expect(ops[2].Op).To(Equal("test"))
+		Expect(ops[2].Value).To(HaveKeyWithValue("name", "shell"))
+		Expect(ops[3]).To(Equal(jsonPatchOperation{Op: "remove", Path: "/spec/template/spec/containers/2"}))
+		Expect(ops[5]).To(Equal(jsonPatchOperation{Op: "remove", Path: "/spec/template/spec/containers/1"}))
+
+		Expect(staleListItems(desired, desired)).To(BeEmpty())
+
+		By("removing a list set where the operator sets none")
+		live = desired.DeepCopy()
+		live.Spec.Template.Spec.Containers[0].Args = []string{"--debug"}
+		Expect(deploymentDrift(desired, live, true)).To(Equal([]string{"spec.template.spec.containers[webapp].args"}))
+		Expect(staleListItems(desired, live)).To(Equal([]jsonPatchOperation{
+			{Op: "test", Path: "/spec/template/spec/containers/0/args", Value: []interface{}{"--debug"}},
+			{Op: "remove", Path: "/spec/template/spec/containers/0/args"},
+		}))
+	})
+})
+
+var _ = Describe("WebApp disruption budget", func() {
+	const resourceName = "disruption-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should follow the replica count and the disruption settings", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec:       appv1alpha1.WebAppSpec{Image: "nginx:1.25", Replicas: ptr.To[int32](3)},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("creating a default budget for several replicas")
+		reconcileOnce()
+		reconcileOnce()
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(k8sClient.Get(ctx, key, pdb)).To(Succeed())
+		Expect(pdb.Spec.MaxUnavailable).To(HaveValue(Equal(intstr.FromInt32(1))))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+
+		By("switching to minAvailable")
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		webapp.Spec.Disruption = &appv1alpha1.DisruptionSpec{MinAvailable: ptr.To(intstr.FromInt32(2))}
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, pdb)).To(Succeed())
+		Expect(pdb.Spec.MinAvailable).To(HaveValue(Equal(intstr.FromInt32(2))))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+		By("scaling down to a single replica")
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		webapp.Spec.Replicas = ptr.To[int32](1)
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &policyv1.PodDisruptionBudget{}))).To(BeTrue())
+	})
+})
+
+var _ = Describe("WebApp storage expansion", func() {
+	const resourceName = "storage-expansion-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should report a clear reason when the storage class cannot expand volumes", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		class := &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: resourceName},
+			Provisioner:          "example.com/fixed",
+			AllowVolumeExpansion: ptr.To(false),
+		}
+		Expect(k8sClient.Create(ctx, class)).To(Succeed())
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image: "nginx:1.25",
+				Storage: &appv1alpha1.StorageSpec{
+					Size:             resource.MustParse("1Gi"),
+					StorageClassName: ptr.To(class.Name),
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, class)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.Storage).NotTo(BeNil())
+		Expect(meta.IsStatusConditionFalse(webapp.Status.Conditions, appv1alpha1.TypeFileSystemResizePending)).To(BeTrue())
+
+		By("growing the requested size")
+		webapp.Spec.Storage.Size = resource.MustParse("2Gi")
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		degraded := meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Reason).To(Equal("StorageExpansionNotSupported"))
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-pvc", Namespace: "default"}, pvc)).To(Succeed())
+		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("1Gi"))
+	})
+})
+
+var _ = Describe("WebApp metrics", func() {
+	webapp := &appv1alpha1.WebApp{ObjectMeta: metav1.ObjectMeta{Name: "metrics-test", Namespace: "default"}}
+
+	It("should export one series per condition status and drop them on deletion", func() {
+		setCondition(webapp, appv1alpha1.TypeDegraded, metav1.ConditionTrue, "DeploymentFailed", "boom")
+		recordConditionMetrics(webapp)
+		recordChildOperation(webapp, "service", "create")
+
+		Expect(testutil.ToFloat64(webappCondition.WithLabelValues("default", "metrics-test", "Degraded", "true"))).
+			To(Equal(1.0))
+		Expect(testutil.ToFloat64(webappCondition.WithLabelValues("default", "metrics-test", "Degraded", "false"))).
+			To(Equal(0.0))
+		Expect(testutil.ToFloat64(webappChildOperations.WithLabelValues("default", "metrics-test", "service", "create"))).
+			To(Equal(1.0))
+
+		deleteWebAppMetrics("default", "metrics-test")
+		labels := prometheus.Labels{"namespace": "default", "name": "metrics-test"}
+		Expect(webappCondition.DeletePartialMatch(labels)).To(BeZero())
+		Expect(webappChildOperations.DeletePartialMatch(labels)).To(BeZero())
+	})
+})
+
+var _ = Describe("WebApp events", func() {
+	It("should emit Degraded reasons as Warning events without repeating them", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &WebAppReconciler{Recorder: recorder}
+		webapp := &appv1alpha1.WebApp{ObjectMeta: metav1.ObjectMeta{
+			Name: "events-test", Namespace: "default", UID: "events-test-uid",
+		}}
+
+		reconciler.markDegraded(webapp, "StorageShrinkRejected", fmt.Errorf("cannot shrink"))
+		reconciler.markDegraded(webapp, "StorageShrinkRejected", fmt.Errorf("cannot shrink"))
+		reconciler.recordEvent(webapp, corev1.EventTypeNormal, "Created", "created %s %s", "service", "events-test")
+
+		Expect(recorder.Events).To(HaveLen(2))
+		Expect(<-recorder.Events).To(Equal("Warning StorageShrinkRejected cannot shrink"))
+		Expect(<-recorder.Events).To(Equal("Normal Created created service events-test"))
+		Expect(meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeDegraded)).To(BeTrue())
+	})
+
+	It("should allow a repeated event once the repeat interval has passed", func() {
+		var limiter eventLimiter
+		now := time.Now()
+		Expect(limiter.allow("a", now)).To(BeTrue())
+		Expect(limiter.allow("a", now.Add(time.Minute))).To(BeFalse())
+		Expect(limiter.allow("b", now.Add(time.Minute))).To(BeTrue())
+		Expect(limiter.allow("a", now.Add(eventRepeatInterval))).To(BeTrue())
+	})
+})
+
+var _ = Describe("rolloutStateForDeployment", func() {
+	deployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+			Status:     status,
+		}
+	}
+
+	It("should wait for the deployment controller to observe the current generation", func() {
+		state := rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+		}))
+		Expect(state.available).To(Equal(metav1.ConditionFalse))
+		Expect(state.progressing).To(Equal(metav1.ConditionTrue))
+		Expect(state.progressingReason).To(Equal("RolloutPending"))
+	})
+
+	It("should not report Available while old replicas still serve traffic", func() {
+		state := rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 2, AvailableReplicas: 3,
+		}))
+		Expect(state.available).To(Equal(metav1.ConditionFalse))
+		Expect(state.availableMessage).To(HavePrefix("2 of 3"))
+		Expect(state.progressingReason).To(Equal("RollingOut"))
+	})
+
+	It("should report a complete rollout as Available", func() {
+		state := rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3,
+		}))
+		Expect(state.available).To(Equal(metav1.ConditionTrue))
+		Expect(state.progressing).To(Equal(metav1.ConditionFalse))
+		Expect(state.progressingReason).To(Equal("RolloutComplete"))
+		Expect(state.stuck).To(BeFalse())
+	})
+
+	It("should report a rollout past its progress deadline as stuck", func() {
+		state := rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1, AvailableReplicas: 3,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse,
+				Reason: progressDeadlineExceededReason, Message: `ReplicaSet "web-5d8" has timed out progressing.`,
+			}},
+		}))
+		Expect(state.stuck).To(BeTrue())
+		Expect(state.progressing).To(Equal(metav1.ConditionFalse))
+		Expect(state.progressingMessage).To(ContainSubstring("timed out"))
+		Expect(state.available).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report pods rejected by Pod Security admission", func() {
+		message := `pods "web-5d8-x7k2p" is forbidden: violates PodSecurity "restricted:latest": ` +
+			`allowPrivilegeEscalation != false (container "webapp" must set securityContext.allowPrivilegeEscalation=false)`
+		state := rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue,
+				Reason: "FailedCreate", Message: message,
+			}},
+		}))
+		Expect(state.podSecurityRejection).To(Equal(message))
+
+		state = rolloutStateForDeployment(deployment(appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue,
+				Reason: "FailedCreate", Message: `pods "web-5d8-x7k2p" is forbidden: exceeded quota: compute`,
+			}},
+		}))
+		Expect(state.podSecurityRejection).To(BeEmpty())
+	})
+})
+
+var _ = Describe("storageVolumesForWebApp", func() {
+	It("should combine the storage shorthand with the named volumes", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			Storage: &appv1alpha1.StorageSpec{Size: resource.MustParse("1Gi")},
+			Volumes: []appv1alpha1.VolumeSpec{
+				{
+					Name:        "shared",
+					Size:        resource.MustParse("5Gi"),
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					MountPath:   "/srv/shared",
+					SubPath:     "app",
+					ReadOnly:    true,
+				},
+				{
+					Name:       "raw",
+					Size:       resource.MustParse("10Gi"),
+					VolumeMode: ptr.To(corev1.PersistentVolumeBlock),
+					MountPath:  "/dev/xvda",
+				},
+			},
+		}
+
+		volumes := storageVolumesForWebApp("shop", spec)
+		Expect(volumes).To(HaveLen(3))
+		Expect(volumes[0].ClaimName).To(Equal("shop-pvc"))
+		Expect(volumes[0].AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+		Expect(volumes[1].ClaimName).To(Equal("shop-shared"))
+		Expect(volumes[2].ClaimName).To(Equal("shop-raw"))
+		Expect(volumes[2].AccessModes).To(ConsistOf(corev1.ReadWriteOnce))
+
+		Expect(podVolumesForWebApp(volumes)).To(HaveLen(3))
+		Expect(volumeMountsForWebApp(volumes)).To(Equal([]corev1.VolumeMount{
+			{Name: appv1alpha1.StorageVolumeName, MountPath: appv1alpha1.DefaultMountPath},
+			{Name: "shared", MountPath: "/srv/shared", SubPath: "app", ReadOnly: true},
+		}))
+		Expect(volumeDevicesForWebApp(volumes)).To(Equal([]corev1.VolumeDevice{
+			{Name: "raw", DevicePath: "/dev/xvda"},
+		}))
+	})
+
+	It("should return nothing when no storage is configured", func() {
+		volumes := storageVolumesForWebApp("shop", &appv1alpha1.WebAppSpec{})
+		Expect(volumes).To(BeEmpty())
+		Expect(podVolumesForWebApp(volumes)).To(BeNil())
+		Expect(volumeMountsForWebApp(volumes)).To(BeNil())
+		Expect(scratchPodVolumesForWebApp(&appv1alpha1.WebAppSpec{})).To(BeNil())
+	})
+
+	It("should add scratch volumes as emptyDirs, mounted in the main container when asked", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			ScratchVolumes: []appv1alpha1.ScratchVolumeSpec{
+				{Name: "models", MountPath: "/models", SizeLimit: ptr.To(resource.MustParse("2Gi"))},
+				{Name: "tmp", Medium: corev1.StorageMediumMemory},
+			},
+		}
+
+		Expect(scratchPodVolumesForWebApp(spec)).To(Equal([]corev1.Volume{
+			{Name: "models", VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: ptr.To(resource.MustParse("2Gi"))},
+			}},
+			{Name: "tmp", VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+			}},
+		}))
+		Expect(scratchVolumeMountsForWebApp(spec)).To(Equal([]corev1.VolumeMount{
+			{Name: "models", MountPath: "/models"},
+		}))
+	})
+})
+
+var _ = Describe("WebApp exposure", func() {
+	const resourceName = "expose-test"
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	It("should create an Ingress, report the URL and remove the Ingress when unexposed", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: appv1alpha1.WebAppSpec{
+				Image: "nginx:1.25",
+				Expose: &appv1alpha1.ExposeSpec{
+					Hosts: []string{"shop.example.com"},
+					TLS:   &appv1alpha1.ExposeTLSSpec{SecretName: "shop-tls"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+
+		for range 2 {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		ingress := &networkingv1.Ingress{}
+		Expect(k8sClient.Get(ctx, key, ingress)).To(Succeed())
+		Expect(ingress.Spec.Rules).To(HaveLen(1))
+		Expect(ingress.Spec.Rules[0].Host).To(Equal("shop.example.com"))
+		Expect(ingress.Spec.Rules[0].HTTP.Paths[0].Path).To(Equal(appv1alpha1.DefaultExposePath))
+		Expect(ingress.Spec.TLS[0].SecretName).To(Equal("shop-tls"))
+
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.URL).To(Equal("https://shop.example.com/"))
+
+		By("removing spec.expose")
+		webapp.Spec.Expose = nil
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &networkingv1.Ingress{}))).To(BeTrue())
+		Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		Expect(webapp.Status.URL).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WebApp service", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "service-test", Namespace: "default"}
+
+	It("should expose every port on a load balancer and switch to a headless service", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image: "nginx:1.25",
+				Ports: []appv1alpha1.PortSpec{
+					{Name: "web", ContainerPort: 8080, ServicePort: 80, AppProtocol: ptr.To(appv1alpha1.AppProtocolH2C)},
+					{Name: "metrics", ContainerPort: 9100},
+				},
+				Service: &appv1alpha1.ServiceSpec{
+					Type:                  appv1alpha1.ServiceTypeLoadBalancer,
+					Annotations:           map[string]string{"example.com/lb-scheme": "internal"},
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		}
+		reconcileOnce()
+		reconcileOnce()
+
+		svc := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, key, svc)).To(Succeed())
+		Expect(svc.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(svc.Spec.ExternalTrafficPolicy).To(Equal(corev1.ServiceExternalTrafficPolicyLocal))
+		Expect(svc.Annotations).To(HaveKeyWithValue("example.com/lb-scheme", "internal"))
+		Expect(svc.Spec.Ports).To(HaveLen(2))
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(80)))
+		Expect(svc.Spec.Ports[0].TargetPort).To(Equal(intstr.FromInt32(8080)))
+		Expect(svc.Spec.Ports[0].AppProtocol).To(HaveValue(Equal("kubernetes.io/h2c")))
+		Expect(svc.Spec.Ports[1].Port).To(Equal(int32(9100)))
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.Containers[0].Ports).To(HaveLen(2))
+
+		By("reporting the load balancer address")
+		svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+		Expect(k8sClient.Status().Update(ctx, svc)).To(Succeed())
+		reconcileOnce()
+		Expect(webapp.Status.Service).NotTo(BeNil())
+		Expect(webapp.Status.Service.Type).To(Equal(appv1alpha1.ServiceTypeLoadBalancer))
+		Expect(webapp.Status.Service.LoadBalancerIngress).To(Equal([]string{"203.0.113.10"}))
+
+		By("switching to a headless service")
+		webapp.Spec.Service = &appv1alpha1.ServiceSpec{Type: appv1alpha1.ServiceTypeHeadless}
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, svc)).To(Succeed())
+		Expect(svc.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+		Expect(svc.Annotations).NotTo(HaveKey("example.com/lb-scheme"))
+		Expect(webapp.Status.Service.Type).To(Equal(appv1alpha1.ServiceTypeHeadless))
+		Expect(webapp.Status.Service.ClusterIP).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WebApp scheduling", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "scheduling-test", Namespace: "default"}
+
+	It("should apply the scheduling fields to the pod template and keep them in sync", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:        "nginx:1.25",
+				NodeSelector: map[string]string{"node-pool": "web"},
+				Tolerations: []corev1.Toleration{{
+					Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule,
+				}},
+				Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+						Weight: 50,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							TopologyKey:   corev1.LabelHostname,
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cache"}},
+						},
+					}},
+				}},
+				Spread: appv1alpha1.SpreadZone,
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		}
+		reconcileOnce()
+		reconcileOnce()
+
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		pod := dep.Spec.Template.Spec
+		Expect(pod.NodeSelector).To(Equal(map[string]string{"node-pool": "web"}))
+		Expect(pod.Tolerations).To(HaveLen(1))
+		Expect(pod.Affinity.PodAntiAffinity).NotTo(BeNil())
+		Expect(pod.TopologySpreadConstraints).To(HaveLen(2))
+		Expect(pod.TopologySpreadConstraints[0].TopologyKey).To(Equal(corev1.LabelTopologyZone))
+
+		By("removing the fields from the spec")
+		webapp.Spec.NodeSelector = nil
+		webapp.Spec.Tolerations = nil
+		webapp.Spec.Affinity = nil
+		webapp.Spec.Spread = ""
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		pod = dep.Spec.Template.Spec
+		Expect(pod.NodeSelector).To(BeEmpty())
+		Expect(pod.Tolerations).To(BeEmpty())
+		Expect(pod.Affinity).To(BeNil())
+		Expect(pod.TopologySpreadConstraints).To(BeEmpty())
+	})
+})
+
+var _ = Describe("topologySpreadConstraintsForWebApp", func() {
+	It("should add zone and hostname constraints for the zone preset", func() {
+		spec := &appv1alpha1.WebAppSpec{Spread: appv1alpha1.SpreadZone}
+		constraints := topologySpreadConstraintsForWebApp("shop", spec)
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0].TopologyKey).To(Equal(corev1.LabelTopologyZone))
+		Expect(constraints[1].TopologyKey).To(Equal(corev1.LabelHostname))
+		for _, c := range constraints {
+			Expect(c.MaxSkew).To(Equal(int32(1)))
+			Expect(c.WhenUnsatisfiable).To(Equal(corev1.ScheduleAnyway))
+			Expect(c.LabelSelector).To(Equal(mainPodSelectorForWebApp("shop")))
+		}
+	})
+
+	It("should keep a constraint of the spec over the preset one with its topology key", func() {
+		strict := corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: labelsForWebApp("shop")},
+		}
+		spec := &appv1alpha1.WebAppSpec{
+			Spread:                    appv1alpha1.SpreadZone,
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{strict},
+		}
+		constraints := topologySpreadConstraintsForWebApp("shop", spec)
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0]).To(Equal(strict))
+		Expect(constraints[1].TopologyKey).To(Equal(corev1.LabelHostname))
+
+		spec.Spread = ""
+		Expect(topologySpreadConstraintsForWebApp("shop", spec)).To(Equal([]corev1.TopologySpreadConstraint{strict}))
+	})
+})
+
+var _ = Describe("WebApp security context", func() {
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "security-test", Namespace: "default"}
+
+	It("should run the pods with the restricted defaults and report Pod Security rejections", func() {
+		reconciler := &WebAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		webapp := &appv1alpha1.WebApp{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: appv1alpha1.WebAppSpec{
+				Image:         "nginx:1.25",
+				InitContainer: &appv1alpha1.InitContainerSpec{Name: "init", Image: "busybox:1.36"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, webapp)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, webapp)).To(Succeed())
+		})
+		reconcileOnce := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, key, webapp)).To(Succeed())
+		}
+		reconcileOnce()
+		reconcileOnce()
+
+		dep := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		pod := dep.Spec.Template.Spec
+		Expect(pod.SecurityContext.RunAsNonRoot).To(HaveValue(BeTrue()))
+		Expect(pod.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+		for _, c := range append(pod.InitContainers, pod.Containers...) {
+			Expect(c.SecurityContext.AllowPrivilegeEscalation).To(HaveValue(BeFalse()), c.Name)
+			Expect(c.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")), c.Name)
+		}
+
+		By("reporting pods rejected by Pod Security admission as Degraded")
+		dep.Status.ObservedGeneration = dep.Generation
+		dep.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue, Reason: "FailedCreate",
+			Message: `pods "security-test-5d8-x7k2p" is forbidden: violates PodSecurity "restricted:latest": ` +
+				`seccompProfile (pod or container "webapp" must set securityContext.seccompProfile.type)`,
+		}}
+		Expect(k8sClient.Status().Update(ctx, dep)).To(Succeed())
+		reconcileOnce()
+		degraded := meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("PodSecurityRejected"))
+
+		By("opting out of the defaults")
+		webapp.Spec.SecurityContext = &appv1alpha1.SecurityContextSpec{Profile: appv1alpha1.SecurityProfileNone}
+		Expect(k8sClient.Update(ctx, webapp)).To(Succeed())
+		reconcileOnce()
+		Expect(k8sClient.Get(ctx, key, dep)).To(Succeed())
+		Expect(dep.Spec.Template.Spec.SecurityContext).To(SatisfyAny(BeNil(), Equal(&corev1.PodSecurityContext{})))
+		Expect(dep.Spec.Template.Spec.Containers[0].SecurityContext).To(BeNil())
+	})
+})
+
+var _ = Describe("applySecurityContext", func() {
+	podSpec := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}, {Name: "log-shipper"}},
+			Containers:     []corev1.Container{{Name: appv1alpha1.MainContainerName}},
+		}
+	}
+
+	It("should apply the restricted defaults to the pod and every container", func() {
+		pod := podSpec()
+		applySecurityContext(pod, &appv1alpha1.WebAppSpec{})
+		Expect(pod.SecurityContext).To(Equal(&corev1.PodSecurityContext{
+			RunAsNonRoot:   ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		}))
+		for _, c := range append(pod.InitContainers, pod.Containers...) {
+			Expect(c.SecurityContext).To(Equal(&corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			}), c.Name)
+		}
+	})
+
+	It("should apply the overrides on top of the restricted defaults", func() {
+		pod := podSpec()
+		applySecurityContext(pod, &appv1alpha1.WebAppSpec{SecurityContext: &appv1alpha1.SecurityContextSpec{
+			Profile:                appv1alpha1.SecurityProfileRestricted,
+			RunAsUser:              ptr.To[int64](101),
+			FSGroup:                ptr.To[int64](101),
+			ReadOnlyRootFilesystem: true,
+			AddCapabilities:        []corev1.Capability{"NET_BIND_SERVICE"},
+		}})
+		Expect(pod.SecurityContext.RunAsUser).To(HaveValue(Equal(int64(101))))
+		Expect(pod.SecurityContext.FSGroup).To(HaveValue(Equal(int64(101))))
+		Expect(pod.SecurityContext.RunAsNonRoot).To(HaveValue(BeTrue()))
+		main := pod.Containers[0].SecurityContext
+		Expect(main.ReadOnlyRootFilesystem).To(HaveValue(BeTrue()))
+		Expect(main.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+		Expect(main.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_BIND_SERVICE")))
+	})
+
+	It("should set only the given fields with the None profile", func() {
+		pod := podSpec()
+		applySecurityContext(pod, &appv1alpha1.WebAppSpec{SecurityContext: &appv1alpha1.SecurityContextSpec{
+			Profile: appv1alpha1.SecurityProfileNone,
+		}})
+		Expect(pod.SecurityContext).To(BeNil())
+		Expect(pod.Containers[0].SecurityContext).To(BeNil())
+
+		applySecurityContext(pod, &appv1alpha1.WebAppSpec{SecurityContext: &appv1alpha1.SecurityContextSpec{
+			Profile:                appv1alpha1.SecurityProfileNone,
+			RunAsUser:              ptr.To[int64](0),
+			ReadOnlyRootFilesystem: true,
+		}})
+		Expect(pod.SecurityContext).To(Equal(&corev1.PodSecurityContext{RunAsUser: ptr.To[int64](0)}))
+		Expect(pod.InitContainers[1].SecurityContext).To(Equal(&corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)}))
+	})
+})
+
+var _ = Describe("servicePortsForWebApp", func() {
+	It("should expose Spec.Port as a single port named http", func() {
+		spec := &appv1alpha1.WebAppSpec{Port: 8080}
+		Expect(servicePortsForWebApp(spec)).To(Equal([]corev1.ServicePort{{
+			Name: appv1alpha1.DefaultPortName, Port: 8080, TargetPort: intstr.FromInt32(8080), Protocol: corev1.ProtocolTCP,
+		}}))
+		Expect(containerPortsForWebApp(spec)).To(Equal([]corev1.ContainerPort{
+			{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+		}))
+		Expect(primaryServicePort(spec)).To(Equal(int32(8080)))
+	})
+
+	It("should route the service port of the first port and map the app protocol", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			Port: 9090,
+			Ports: []appv1alpha1.PortSpec{
+				{Name: "grpc", ContainerPort: 9090, ServicePort: 443, Protocol: corev1.ProtocolTCP,
+					AppProtocol: ptr.To(appv1alpha1.AppProtocolGRPC)},
+				{Name: "dns", ContainerPort: 5353, Protocol: corev1.ProtocolUDP},
+			},
+		}
+		ports := servicePortsForWebApp(spec)
+		Expect(ports).To(HaveLen(2))
+		Expect(ports[0].AppProtocol).To(HaveValue(Equal("grpc")))
+		Expect(ports[1]).To(Equal(corev1.ServicePort{
+			Name: "dns", Port: 5353, TargetPort: intstr.FromInt32(5353), Protocol: corev1.ProtocolUDP,
+		}))
+		Expect(containerPortsForWebApp(spec)[1].Name).To(Equal("dns"))
+		Expect(primaryServicePort(spec)).To(Equal(int32(443)))
+	})
+})
+
+var _ = Describe("exposeTypeForWebApp", func() {
+	It("should honor an explicit type", func() {
+		spec := &appv1alpha1.ExposeSpec{Type: appv1alpha1.ExposeTypeIngress, Gateway: &appv1alpha1.GatewayReference{Name: "gw"}}
+		Expect(exposeTypeForWebApp(spec, true)).To(Equal(appv1alpha1.ExposeTypeIngress))
+	})
+
+	It("should pick an HTTPRoute only when a Gateway is named and the API is served", func() {
+		spec := &appv1alpha1.ExposeSpec{Gateway: &appv1alpha1.GatewayReference{Name: "gw"}}
+		Expect(exposeTypeForWebApp(spec, true)).To(Equal(appv1alpha1.ExposeTypeHTTPRoute))
+		Expect(exposeTypeForWebApp(spec, false)).To(Equal(appv1alpha1.ExposeTypeIngress))
+		Expect(exposeTypeForWebApp(&appv1alpha1.ExposeSpec{}, true)).To(Equal(appv1alpha1.ExposeTypeIngress))
+	})
+})
+
+var _ = Describe("httpRouteSpecForWebApp", func() {
+	It("should attach to the Gateway and forward every path to the Service", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			Port: 8080,
+			Expose: &appv1alpha1.ExposeSpec{
+				Hosts:   []string{"shop.example.com"},
+				Paths:   []string{"/", "/api"},
+				Gateway: &appv1alpha1.GatewayReference{Name: "public", Namespace: "gateways"},
+			},
+		}
+
+		route := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		Expect(unstructured.SetNestedField(route.Object, httpRouteSpecForWebApp("shop", spec), "spec")).To(Succeed())
+
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		Expect(parentRefs).To(ConsistOf(map[string]interface{}{"name": "public", "namespace": "gateways"}))
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		Expect(hostnames).To(Equal([]string{"shop.example.com"}))
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		Expect(rules).To(HaveLen(1))
+		rule := rules[0].(map[string]interface{})
+		Expect(rule["matches"]).To(HaveLen(2))
+		Expect(rule["backendRefs"]).To(ConsistOf(map[string]interface{}{"name": "shop", "port": int64(8080)}))
+	})
+})
+
+var _ = Describe("urlForExpose", func() {
+	It("should use the first concrete host and path", func() {
+		spec := &appv1alpha1.ExposeSpec{Hosts: []string{"*.example.com", "shop.example.com"}, Paths: []string{"/app"}}
+		Expect(urlForExpose(spec)).To(Equal("http://shop.example.com/app"))
+	})
+
+	It("should report nothing when every host is a wildcard", func() {
+		Expect(urlForExpose(&appv1alpha1.ExposeSpec{Hosts: []string{"*.example.com"}})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("configReferencesForWebApp", func() {
+	It("should collect sorted, unique names from env, envFrom and the init container", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			Env: []corev1.EnvVar{
+				{Name: "A", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}, Key: "a",
+				}}},
+				{Name: "B", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password",
+				}}},
+			},
+			EnvFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app"}}},
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "shared"}}},
+			},
+			InitContainer: &appv1alpha1.InitContainerSpec{
+				Env: []corev1.EnvVar{
+					{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "api"}, Key: "token",
+					}}},
+				},
+			},
+			Sidecars: []appv1alpha1.ContainerSpec{{
+				Name: "log-shipper",
+				EnvFrom: []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "logs"}}},
+				},
+			}},
+		}
+
+		configMaps, secrets := configReferencesForWebApp(spec)
+		Expect(configMaps).To(Equal([]string{"app", "shared"}))
+		Expect(secrets).To(Equal([]string{"api", "db", "logs"}))
+	})
+})
+
+var _ = Describe("initContainersForWebApp", func() {
+	It("should order the init container, the listed init containers and the sidecars", func() {
+		spec := &appv1alpha1.WebAppSpec{
+			InitContainer: &appv1alpha1.InitContainerSpec{Name: "init", Image: "busybox:1.36"},
+			InitContainers: []appv1alpha1.ContainerSpec{{
+				Name:         "migrate",
+				Image:        "migrate:1.0",
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}},
+			Sidecars: []appv1alpha1.ContainerSpec{{
+				Name:  "log-shipper",
+				Image: "fluent-bit:3.0",
+				Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 2020}},
+			}},
+		}
+
+		containers := initContainersForWebApp(spec)
+		Expect(containers).To(HaveLen(3))
+		Expect(containers[0].Name).To(Equal("init"))
+		Expect(containers[1].Name).To(Equal("migrate"))
+		Expect(containers[1].RestartPolicy).To(BeNil())
+		Expect(containers[1].VolumeMounts).To(Equal(spec.InitContainers[0].VolumeMounts))
+		Expect(containers[2].Name).To(Equal("log-shipper"))
+		Expect(containers[2].RestartPolicy).To(Equal(ptr.To(corev1.ContainerRestartPolicyAlways)))
+		Expect(containers[2].Ports).To(Equal(spec.Sidecars[0].Ports))
+	})
+
+	It("should return nil without init containers or sidecars", func() {
+		Expect(initContainersForWebApp(&appv1alpha1.WebAppSpec{})).To(BeNil())
+	})
+})
+
+var _ = Describe("resourceSummaryForPod", func() {
+	It("should add sidecars to the main container and cover the largest init container", func() {
+		podSpec := &corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name:          "proxy",
+					RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+					},
+				},
+				{
+					Name: "migrate",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+					},
+				},
+			},
+			Containers: []corev1.Container{{
+				Name: appv1alpha1.MainContainerName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("256Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				},
+			}},
+		}
+
+		summary := resourceSummaryForPod(podSpec, 3)
+		Expect(summary.PodRequests.Cpu().String()).To(Equal("2100m"))
+		Expect(summary.PodRequests.Memory().String()).To(Equal("256Mi"))
+		Expect(summary.TotalRequests.Cpu().String()).To(Equal("6300m"))
+		Expect(summary.TotalRequests.Memory().String()).To(Equal("768Mi"))
+
+		// Memory is unbounded because the init containers set no memory limit.
+		Expect(summary.PodLimits).To(HaveLen(1))
+		Expect(summary.PodLimits.Cpu().String()).To(Equal("2200m"))
+		Expect(summary.TotalLimits.Cpu().String()).To(Equal("6600m"))
+	})
+
+	It("should report nothing when no container sets resources", func() {
+		podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: appv1alpha1.MainContainerName}}}
+
+		summary := resourceSummaryForPod(podSpec, 2)
+		Expect(summary.PodRequests).To(BeNil())
+		Expect(summary.PodLimits).To(BeNil())
+		Expect(summary.TotalRequests).To(BeNil())
+		Expect(summary.TotalLimits).To(BeNil())
+	})
+})
+
+var _ = Describe("probesForWebApp", func() {
+	It("should expand healthCheck into HTTP probes against the application port", func() {
+		spec := &appv1alpha1.WebAppSpec{Port: 9090, HealthCheck: &appv1alpha1.HealthCheckSpec{Path: "/healthz"}}
+
+		liveness, readiness := probesForWebApp(spec)
+		for _, probe := range []*corev1.Probe{liveness, readiness} {
+			Expect(probe).NotTo(BeNil())
+			Expect(probe.HTTPGet).NotTo(BeNil())
+			Expect(probe.HTTPGet.Path).To(Equal("/healthz"))
+			Expect(probe.HTTPGet.Port).To(Equal(intstr.FromInt32(9090)))
+		}
+	})
+
+	It("should prefer explicit probes over the healthCheck shorthand", func() {
+		explicit := &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(9090)}},
+		}
+		spec := &appv1alpha1.WebAppSpec{
+			Port:           9090,
+			ReadinessProbe: explicit,
+			HealthCheck:    &appv1alpha1.HealthCheckSpec{Path: "/healthz"},
+		}
+
+		liveness, readiness := probesForWebApp(spec)
+		Expect(readiness).To(BeIdenticalTo(explicit))
+		Expect(liveness.HTTPGet.Path).To(Equal("/healthz"))
+	})
+
+	It("should leave both probes unset when nothing is configured", func() {
+		liveness, readiness := probesForWebApp(&appv1alpha1.WebAppSpec{Port: 8080})
+		Expect(liveness).To(BeNil())
+		Expect(readiness).To(BeNil())
+	})
+})
@@ -0,0 +1,349 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// appliedHashAnnotation is set on the Deployment to a hash of the Deployment spec the
+// operator last applied. A live Deployment that differs from the desired one while the
+// hash is unchanged has drifted; when the hash changed, the WebApp spec changed instead.
+const appliedHashAnnotation = "app.54b3r.io/applied-hash"
+
+// maxDriftFields caps the number of fields listed in the Drifted condition message.
+const maxDriftFields = 10
+
+// appliedHashForDeployment hashes the spec of the desired Deployment. The replica count is
+// left out while autoscaling is enabled, since it then follows the autoscaler.
+func appliedHashForDeployment(desired *appsv1.Deployment, autoscaling bool) (string, error) {
+	spec := desired.Spec.DeepCopy()
+	if autoscaling {
+		spec.Replicas = nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("hashing deployment spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// applyDeployment applies the desired Deployment according to the drift policy. When the
+// WebApp spec is unchanged since the last apply but the live Deployment differs from it,
+// Enforce reverts the drift while Warn and Ignore leave the live Deployment alone. The
+// Drifted condition reports the outcome. existing is nil when the Deployment does not exist.
+func (r *WebAppReconciler) applyDeployment(ctx context.Context, webapp *appv1alpha1.WebApp,
+	policy appv1alpha1.DriftPolicy, desired, existing *appsv1.Deployment, autoscaling bool) error {
+	var drift []string
+	if existing != nil && existing.Annotations[appliedHashAnnotation] == desired.Annotations[appliedHashAnnotation] {
+		var err error
+		if drift, err = deploymentDrift(desired, existing, !autoscaling); err != nil {
+			return err
+		}
+	}
+	setDriftedCondition(webapp, policy, drift)
+
+	if len(drift) > 0 && policy != appv1alpha1.DriftPolicyEnforce {
+		logf.FromContext(ctx).Info("leaving drifted deployment alone", "name", desired.Name,
+			"driftPolicy", policy, "fields", drift)
+		r.recordDriftEvent(webapp, policy)
+		return nil
+	}
+
+	want := desired.DeepCopy()
+	if err := r.apply(ctx, webapp, desired, "deployment"); err != nil {
+		return err
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	webappDriftCorrections.WithLabelValues(webapp.Namespace, webapp.Name).Inc()
+	r.recordDriftEvent(webapp, policy)
+
+	// Apply only takes back the fields the operator sets. List items added by other
+	// managers, such as an extra env var or container, survive it, so remove them with a
+	// JSON patch. Unlike an update, the patch claims no fields for the operator, which
+	// keeps managing the Deployment through apply alone.
+	ops, err := staleListItems(want, desired)
+	if err != nil || len(ops) == 0 {
+		return err
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		return fmt.Errorf("encoding stale list items patch: %w", err)
+	}
+	logf.FromContext(ctx).Info("removing list items added to the deployment", "name", desired.Name,
+		"paths", len(ops)/2)
+	if err := r.Patch(ctx, desired, client.RawPatch(types.JSONPatchType, data),
+		client.FieldOwner(fieldManager)); err != nil {
+		return fmt.Errorf("removing list items added to the deployment: %w", err)
+	}
+	recordChildOperation(webapp, "deployment", "update")
+	return nil
+}
+
+// recordDriftEvent emits a Warning event carrying the Drifted condition set for policy.
+// Nothing is emitted under Ignore, which removes the condition.
+func (r *WebAppReconciler) recordDriftEvent(webapp *appv1alpha1.WebApp, policy appv1alpha1.DriftPolicy) {
+	cond := meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeDrifted)
+	if policy == appv1alpha1.DriftPolicyIgnore || cond == nil {
+		return
+	}
+	r.recordEvent(webapp, corev1.EventTypeWarning, cond.Reason, "%s", cond.Message)
+}
+
+// setDriftedCondition reports the drifted fields according to the drift policy.
+// The condition is removed under Ignore.
+func setDriftedCondition(webapp *appv1alpha1.WebApp, policy appv1alpha1.DriftPolicy, drift []string) {
+	if policy == appv1alpha1.DriftPolicyIgnore {
+		meta.RemoveStatusCondition(&webapp.Status.Conditions, appv1alpha1.TypeDrifted)
+		return
+	}
+	if len(drift) == 0 {
+		setCondition(webapp, appv1alpha1.TypeDrifted, metav1.ConditionFalse,
+			"NoDrift", "the deployment matches the WebApp spec")
+		return
+	}
+
+	fields := strings.Join(drift[:min(len(drift), maxDriftFields)], ", ")
+	if len(drift) > maxDriftFields {
+		fields += fmt.Sprintf(" and %d more", len(drift)-maxDriftFields)
+	}
+	if policy == appv1alpha1.DriftPolicyWarn {
+		setCondition(webapp, appv1alpha1.TypeDrifted, metav1.ConditionTrue, "DriftDetected",
+			"the deployment differs from the WebApp spec in "+fields)
+		return
+	}
+	setCondition(webapp, appv1alpha1.TypeDrifted, metav1.ConditionTrue, "DriftCorrected",
+		"reverted changes to "+fields)
+}
+
+// deploymentDrift returns the paths of the fields where the live Deployment differs from
+// the desired one: the replica count, when compareReplicas is set, and the pod template.
+// The comparison is semantic: every field set in desired must have the same value in
+// live, and lists must have the same length. Other fields the operator does not set, such
+// as values defaulted by the API server or annotations added by other tools, are not
+// drift, but lists added where the operator sets none are.
+func deploymentDrift(desired, live *appsv1.Deployment, compareReplicas bool) ([]string, error) {
+	var drift []string
+	if compareReplicas && ptr.Deref(desired.Spec.Replicas, 1) != ptr.Deref(live.Spec.Replicas, 1) {
+		drift = append(drift, "spec.replicas")
+	}
+
+	want, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&desired.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("converting desired pod template: %w", err)
+	}
+	got, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&live.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("converting live pod template: %w", err)
+	}
+	return diffFields("spec.template", want, got, drift), nil
+}
+
+// diffFields appends to out the path of every field in want whose value differs in got.
+// List items are labeled by their name when they have one, and by index otherwise.
+func diffFields(path string, want, got interface{}, out []string) []string {
+	switch w := want.(type) {
+	case nil:
+		return out
+	case map[string]interface{}:
+		g, _ := got.(map[string]interface{})
+		if len(w) > 0 && g == nil {
+			return append(out, path)
+		}
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			out = diffFields(path+"."+k, w[k], g[k], out)
+		}
+		for _, k := range addedLists(w, g) {
+			out = append(out, path+"."+k)
+		}
+		return out
+	case []interface{}:
+		g, _ := got.([]interface{})
+		if len(w) != len(g) {
+			return append(out, path)
+		}
+		for i := range w {
+			out = diffFields(path+"["+listItemKey(w[i], i)+"]", w[i], g[i], out)
+		}
+		return out
+	default:
+		if got == nil && reflect.ValueOf(want).IsZero() {
+			return out
+		}
+		if !reflect.DeepEqual(want, got) {
+			return append(out, path)
+		}
+		return out
+	}
+}
+
+// jsonPatchOperation is a single operation of an RFC 6902 JSON patch.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// staleListItems returns the JSON patch operations that remove every list item of the
+// live pod template that matches no item of the same list in the desired one. Each
+// removal is preceded by a test of the item, so a concurrent change fails the patch
+// rather than removing the wrong item.
+func staleListItems(desired, live *appsv1.Deployment) ([]jsonPatchOperation, error) {
+	want, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&desired.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("converting desired pod template: %w", err)
+	}
+	got, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&live.Spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("converting live pod template: %w", err)
+	}
+	return appendStaleListItems("/spec/template", want, got, nil), nil
+}
+
+// appendStaleListItems appends to ops the removal of every list item in got, at path or
+// below, that matches no item of the same list in want. Items with a name unique in
+// their list match by name; other items match an item of want they fully contain.
+// Removals within a list run from the last index to the first, after those nested in
+// the items it keeps, so every path stays valid while the patch applies.
+func appendStaleListItems(path string, want, got interface{}, ops []jsonPatchOperation) []jsonPatchOperation {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, _ := got.(map[string]interface{})
+		keys := make([]string, 0, len(w))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			ops = appendStaleListItems(path+"/"+jsonPointerEscaper.Replace(k), w[k], g[k], ops)
+		}
+		for _, k := range addedLists(w, g) {
+			listPath := path + "/" + jsonPointerEscaper.Replace(k)
+			ops = append(ops,
+				jsonPatchOperation{Op: "test", Path: listPath, Value: g[k]},
+				jsonPatchOperation{Op: "remove", Path: listPath})
+		}
+		return ops
+	case []interface{}:
+		g, _ := got.([]interface{})
+		matched := make([]bool, len(w))
+		var stale []int
+		for i, item := range g {
+			j := -1
+			for k := range w {
+				if !matched[k] && listItemsMatch(w, k, item) {
+					j = k
+					break
+				}
+			}
+			if j < 0 {
+				stale = append(stale, i)
+				continue
+			}
+			matched[j] = true
+			ops = appendStaleListItems(path+"/"+strconv.Itoa(i), w[j], item, ops)
+		}
+		for _, i := range slices.Backward(stale) {
+			itemPath := path + "/" + strconv.Itoa(i)
+			ops = append(ops,
+				jsonPatchOperation{Op: "test", Path: itemPath, Value: g[i]},
+				jsonPatchOperation{Op: "remove", Path: itemPath})
+		}
+		return ops
+	default:
+		return ops
+	}
+}
+
+// listItemsMatch reports whether got is the live counterpart of want[i]: the item with
+// the same name when want[i] has a name no other item of want has, and otherwise an
+// item that has every field of want[i] with the same value.
+func listItemsMatch(want []interface{}, i int, got interface{}) bool {
+	if name := listItemName(want[i]); name != "" {
+		named := 0
+		for _, item := range want {
+			if listItemName(item) == name {
+				named++
+			}
+		}
+		if named == 1 {
+			return listItemName(got) == name
+		}
+	}
+	return len(diffFields("", want[i], got, nil)) == 0
+}
+
+// listItemName returns the name of a list item, or "" when it has none.
+func listItemName(item interface{}) string {
+	if m, ok := item.(map[string]interface{}); ok {
+		name, _ := m["name"].(string)
+		return name
+	}
+	return ""
+}
+
+// addedLists returns the sorted keys of got that hold a non-empty list and are missing
+// from want: lists another manager added where the operator sets none.
+func addedLists(want, got map[string]interface{}) []string {
+	var keys []string
+	for k, v := range got {
+		if list, ok := v.([]interface{}); ok && len(list) > 0 && want[k] == nil {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// jsonPointerEscaper escapes a key for use as an RFC 6901 JSON pointer segment.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// listItemKey returns the name of a list item, or its index when it has no name.
+func listItemKey(item interface{}, i int) string {
+	if name := listItemName(item); name != "" {
+		return name
+	}
+	return strconv.Itoa(i)
+}
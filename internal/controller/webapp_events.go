@@ -0,0 +1,88 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// eventRepeatInterval is how long an identical event for the same WebApp is suppressed.
+// A reconcile that fails the same way in a hot loop emits one event per interval instead
+// of one per attempt; the event broadcaster's own spam filter then caps the rest.
+const eventRepeatInterval = 5 * time.Minute
+
+// eventLimiterPruneSize is the number of remembered events above which expired entries
+// are dropped, which keeps the limiter's memory bounded by the event rate.
+const eventLimiterPruneSize = 1024
+
+// eventLimiter suppresses repeats of the same event within eventRepeatInterval.
+// The zero value is ready to use and safe for concurrent reconciles.
+type eventLimiter struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// allow reports whether the event identified by key may be emitted at now, and if so
+// remembers it.
+func (l *eventLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if sent, ok := l.last[key]; ok && now.Sub(sent) < eventRepeatInterval {
+		return false
+	}
+	if l.last == nil {
+		l.last = map[string]time.Time{}
+	}
+	l.last[key] = now
+	if len(l.last) > eventLimiterPruneSize {
+		for k, sent := range l.last {
+			if now.Sub(sent) >= eventRepeatInterval {
+				delete(l.last, k)
+			}
+		}
+	}
+	return true
+}
+
+// recordEvent emits an event on the WebApp unless the same event was emitted recently.
+// It is a no-op when the reconciler has no recorder.
+func (r *WebAppReconciler) recordEvent(webapp *appv1alpha1.WebApp, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	message := fmt.Sprintf(messageFmt, args...)
+	key := fmt.Sprintf("%s/%s/%s/%s", webapp.UID, eventType, reason, message)
+	if !r.events.allow(key, r.now()) {
+		return
+	}
+	r.Recorder.Event(webapp, eventType, reason, message)
+}
+
+// markDegraded sets the Degraded condition with the given reason and emits a matching
+// Warning event.
+func (r *WebAppReconciler) markDegraded(webapp *appv1alpha1.WebApp, reason string, err error) {
+	setCondition(webapp, appv1alpha1.TypeDegraded, metav1.ConditionTrue, reason, err.Error())
+	r.recordEvent(webapp, corev1.EventTypeWarning, reason, "%s", err.Error())
+}
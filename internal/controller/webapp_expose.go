@@ -0,0 +1,274 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// httpRouteGVK identifies the Gateway API HTTPRoute kind. HTTPRoutes are handled as
+// unstructured objects so the operator runs on clusters without the Gateway API CRDs
+// and does not need the Gateway API Go module.
+var httpRouteGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1",
+	Kind:    "HTTPRoute",
+}
+
+// hasHTTPRouteAPI reports whether the cluster serves the Gateway API HTTPRoute kind.
+// The manager's REST mapper discovers CRDs lazily, so installing the Gateway API after
+// the operator started is picked up here without a restart.
+func hasHTTPRouteAPI(mapper meta.RESTMapper) (bool, error) {
+	_, err := mapper.RESTMapping(httpRouteGVK.GroupKind(), httpRouteGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("looking up %s: %w", httpRouteGVK.Kind, err)
+	}
+	return true, nil
+}
+
+// reconcileExpose applies or deletes the Ingress or HTTPRoute for the given
+// WebApp and returns its external URL. Only one of the two exists at a time: switching
+// Spec.Expose.Type deletes the object of the other kind.
+func (r *WebAppReconciler) reconcileExpose(ctx context.Context, webapp *appv1alpha1.WebApp) (string, error) {
+	routeAPI, err := hasHTTPRouteAPI(r.RESTMapper())
+	if err != nil {
+		return "", err
+	}
+
+	var exposeType appv1alpha1.ExposeType
+	if webapp.Spec.Expose != nil {
+		spec := webapp.Spec.DeepCopy()
+		spec.ApplyDefaults()
+		exposeType = exposeTypeForWebApp(spec.Expose, routeAPI)
+
+		switch exposeType {
+		case appv1alpha1.ExposeTypeHTTPRoute:
+			if !routeAPI {
+				return "", fmt.Errorf("expose type %s requested but the cluster does not serve %s",
+					appv1alpha1.ExposeTypeHTTPRoute, httpRouteGVK.GroupVersion())
+			}
+			if err := r.reconcileHTTPRoute(ctx, webapp, spec); err != nil {
+				return "", err
+			}
+		default:
+			if err := r.reconcileIngress(ctx, webapp, spec); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Remove whichever kind is no longer wanted, including both when Expose is unset.
+	if exposeType != appv1alpha1.ExposeTypeIngress {
+		if err := r.deleteOwned(ctx, webapp, &networkingv1.Ingress{}, "ingress"); err != nil {
+			return "", err
+		}
+	}
+	if exposeType != appv1alpha1.ExposeTypeHTTPRoute && routeAPI {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(httpRouteGVK)
+		if err := r.deleteOwned(ctx, webapp, route, "httproute"); err != nil {
+			return "", err
+		}
+	}
+
+	if webapp.Spec.Expose == nil {
+		return "", nil
+	}
+	return urlForExpose(webapp.Spec.Expose), nil
+}
+
+// exposeTypeForWebApp resolves the expose type. An explicit type always wins; otherwise
+// an HTTPRoute is chosen when a Gateway is named and the cluster serves the Gateway API.
+func exposeTypeForWebApp(spec *appv1alpha1.ExposeSpec, routeAPI bool) appv1alpha1.ExposeType {
+	if spec.Type != "" {
+		return spec.Type
+	}
+	if spec.Gateway != nil && routeAPI {
+		return appv1alpha1.ExposeTypeHTTPRoute
+	}
+	return appv1alpha1.ExposeTypeIngress
+}
+
+// reconcileIngress server-side applies the Ingress for the given WebApp.
+// It sets an owner reference so the Ingress is garbage-collected with the WebApp.
+func (r *WebAppReconciler) reconcileIngress(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec) error {
+	pathType := networkingv1.PathTypePrefix
+	paths := make([]networkingv1.HTTPIngressPath, 0, len(spec.Expose.Paths))
+	for _, path := range spec.Expose.Paths {
+		paths = append(paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: webapp.Name,
+					Port: networkingv1.ServiceBackendPort{Number: primaryServicePort(spec)},
+				},
+			},
+		})
+	}
+	rules := make([]networkingv1.IngressRule, 0, len(spec.Expose.Hosts))
+	for _, host := range spec.Expose.Hosts {
+		rules = append(rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+			},
+		})
+	}
+
+	desired := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      webapp.Name,
+			Namespace: webapp.Namespace,
+			Labels:    labelsForWebApp(webapp.Name),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: spec.Expose.ClassName,
+			Rules:            rules,
+		},
+	}
+	if spec.Expose.TLS != nil {
+		desired.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      spec.Expose.Hosts,
+			SecretName: spec.Expose.TLS.SecretName,
+		}}
+	}
+
+	// The whole spec is derived from Spec.Expose; annotations added by controllers such as
+	// cert-manager are owned by them and left in place.
+	return r.apply(ctx, webapp, desired, "ingress")
+}
+
+// reconcileHTTPRoute server-side applies the HTTPRoute for the given WebApp.
+// It sets an owner reference so the HTTPRoute is garbage-collected with the WebApp.
+func (r *WebAppReconciler) reconcileHTTPRoute(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec) error {
+	desired := &unstructured.Unstructured{}
+	desired.SetGroupVersionKind(httpRouteGVK)
+	desired.SetName(webapp.Name)
+	desired.SetNamespace(webapp.Namespace)
+	desired.SetLabels(labelsForWebApp(webapp.Name))
+	routeSpec := httpRouteSpecForWebApp(webapp.Name, spec)
+	if err := unstructured.SetNestedField(desired.Object, routeSpec, "spec"); err != nil {
+		return fmt.Errorf("building httproute spec: %w", err)
+	}
+
+	return r.apply(ctx, webapp, desired, "httproute")
+}
+
+// httpRouteSpecForWebApp builds the unstructured HTTPRoute spec: one rule matching every
+// path prefix on every host, forwarding to the managed Service. Integers are int64 as
+// required by the unstructured helpers.
+func httpRouteSpecForWebApp(name string, spec *appv1alpha1.WebAppSpec) map[string]interface{} {
+	parentRef := map[string]interface{}{"name": spec.Expose.Gateway.Name}
+	if spec.Expose.Gateway.Namespace != "" {
+		parentRef["namespace"] = spec.Expose.Gateway.Namespace
+	}
+	if spec.Expose.Gateway.SectionName != "" {
+		parentRef["sectionName"] = spec.Expose.Gateway.SectionName
+	}
+
+	hostnames := make([]interface{}, 0, len(spec.Expose.Hosts))
+	for _, host := range spec.Expose.Hosts {
+		hostnames = append(hostnames, host)
+	}
+	matches := make([]interface{}, 0, len(spec.Expose.Paths))
+	for _, path := range spec.Expose.Paths {
+		matches = append(matches, map[string]interface{}{
+			"path": map[string]interface{}{"type": "PathPrefix", "value": path},
+		})
+	}
+
+	return map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  hostnames,
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": matches,
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": name, "port": int64(primaryServicePort(spec))},
+				},
+			},
+		},
+	}
+}
+
+// deleteOwned deletes the object of obj's kind named after the WebApp, or named like obj
+// when it carries a name, if it exists and is controlled by the WebApp. Objects created
+// by someone else are never touched.
+func (r *WebAppReconciler) deleteOwned(ctx context.Context, webapp *appv1alpha1.WebApp,
+	obj client.Object, kind string) error {
+	name := obj.GetName()
+	if name == "" {
+		name = webapp.Name
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: webapp.Namespace}, obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting %s for cleanup: %w", kind, err)
+	}
+	if !metav1.IsControlledBy(obj, webapp) {
+		return nil
+	}
+	logf.FromContext(ctx).Info("deleting "+kind, "name", name)
+	if err := r.Delete(ctx, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.recordEvent(webapp, corev1.EventTypeNormal, "Deleted", "deleted %s %s", kind, name)
+	return nil
+}
+
+// urlForExpose returns the external URL built from the first concrete host and the first
+// path. Wildcard hosts have no single address, so an empty string is returned when every
+// host is a wildcard.
+func urlForExpose(spec *appv1alpha1.ExposeSpec) string {
+	i := slices.IndexFunc(spec.Hosts, func(host string) bool { return !strings.HasPrefix(host, "*.") })
+	if i < 0 {
+		return ""
+	}
+	scheme := "http"
+	if spec.TLS != nil {
+		scheme = "https"
+	}
+	path := appv1alpha1.DefaultExposePath
+	if len(spec.Paths) > 0 {
+		path = spec.Paths[0]
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, spec.Hosts[i], path)
+}
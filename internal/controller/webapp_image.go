@@ -0,0 +1,438 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// DefaultImageResolveInterval is how often a pinned image tag is resolved again when
+// WebAppReconciler.ImageResolveInterval is unset.
+const DefaultImageResolveInterval = 10 * time.Minute
+
+// manifestMediaTypes are the manifest types accepted from the registry. Multi-platform
+// indexes come first, so the digest is the same on every node architecture.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// registryClient is the HTTP client of the default registry resolver. The timeout keeps
+// an unresponsive registry from stalling reconciles.
+var registryClient = &http.Client{Timeout: 30 * time.Second}
+
+// digestPattern matches the sha256 manifest digests registries return.
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// RegistryCredential is a username and password for a container registry, taken from an
+// image pull secret.
+type RegistryCredential struct {
+	Username string
+	Password string
+}
+
+// ImageResolver resolves an image reference to the digest of its manifest. credentials
+// are those of the pod's image pull secrets that match the image's registry, in the
+// order the kubelet tries them; the registry is accessed anonymously when there are none.
+type ImageResolver interface {
+	Resolve(ctx context.Context, image string, credentials []RegistryCredential) (string, error)
+}
+
+// registryResolver resolves image tags through the Docker Registry HTTP API V2, which
+// every OCI registry serves. It answers Bearer token challenges, such as Docker Hub's,
+// and Basic challenges, with each credential in turn until the registry accepts one.
+type registryResolver struct {
+	client *http.Client
+}
+
+// imageReference is an image reference split into the parts the registry API needs.
+type imageReference struct {
+	host       string
+	repository string
+	tag        string
+}
+
+// parseImageReference splits an image reference without a digest. References without a
+// registry host resolve against Docker Hub, as they do for the kubelet.
+func parseImageReference(image string) (imageReference, error) {
+	ref := imageReference{host: "registry-1.docker.io", repository: image, tag: "latest"}
+	if first, rest, ok := strings.Cut(image, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.host, ref.repository = first, rest
+	}
+	if ref.host == "docker.io" {
+		ref.host = "registry-1.docker.io"
+	}
+	if i := strings.LastIndex(ref.repository, ":"); i > strings.LastIndex(ref.repository, "/") {
+		ref.repository, ref.tag = ref.repository[:i], ref.repository[i+1:]
+	}
+	if ref.host == "registry-1.docker.io" && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+	if ref.repository == "" || ref.tag == "" {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+	return ref, nil
+}
+
+// scheme returns the URL scheme of the registry. Registries on the loopback interface
+// are reached over plain HTTP, as container runtimes do by default.
+func (ref imageReference) scheme() string {
+	host, _, err := net.SplitHostPort(ref.host)
+	if err != nil {
+		host = ref.host
+	}
+	if host == "localhost" || net.ParseIP(host).IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
+
+// Resolve implements ImageResolver.
+func (r *registryResolver) Resolve(ctx context.Context, image string, credentials []RegistryCredential) (string, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	manifestURL := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", ref.scheme(), ref.host, ref.repository, ref.tag)
+
+	resp, err := r.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if len(credentials) == 0 {
+			credentials = []RegistryCredential{{}}
+		}
+		for _, credential := range credentials {
+			authorization, authErr := r.authorization(ctx, challenge, ref, credential)
+			if authErr != nil {
+				err = authErr
+				continue
+			}
+			if resp, err = r.headManifest(ctx, manifestURL, authorization); err != nil {
+				return "", err
+			}
+			if resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden {
+				break
+			}
+		}
+		if resp.StatusCode == http.StatusUnauthorized && err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry %s returned %s for %s:%s", ref.host, resp.Status, ref.repository, ref.tag)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if !digestPattern.MatchString(digest) {
+		return "", fmt.Errorf("registry %s returned no valid digest for %s:%s", ref.host, ref.repository, ref.tag)
+	}
+	return digest, nil
+}
+
+// headManifest sends a HEAD request for a manifest, with an Authorization header when
+// one is given.
+func (r *registryResolver) headManifest(ctx context.Context, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building manifest request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting manifest: %w", err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// authorization returns the Authorization header that answers the registry's challenge
+// with the credential, which is empty for anonymous access.
+func (r *registryResolver) authorization(ctx context.Context, challenge string, ref imageReference,
+	credential RegistryCredential) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		token, err := r.token(ctx, params, ref, credential)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	case strings.EqualFold(scheme, "Basic"):
+		if credential == (RegistryCredential{}) {
+			return "", fmt.Errorf("registry %s requires credentials; add an image pull secret", ref.host)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(credential.Username+":"+credential.Password)), nil
+	default:
+		return "", fmt.Errorf("registry %s requires unsupported authentication %q", ref.host, scheme)
+	}
+}
+
+// token fetches a pull token from the realm named in the parameters of a Bearer
+// challenge, signing in with the credential unless it is empty.
+func (r *registryResolver) token(ctx context.Context, params string, ref imageReference,
+	credential RegistryCredential) (string, error) {
+	values := map[string]string{}
+	for _, param := range strings.Split(params, ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(param), "="); ok {
+			values[k] = strings.Trim(v, `"`)
+		}
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry %s sent an invalid token realm %q", ref.host, values["realm"])
+	}
+	query := realm.Query()
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("building token request: %w", err)
+	}
+	if credential != (RegistryCredential{}) {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting registry token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service for %s returned %s", ref.host, resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding registry token: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// pinnedImage returns the image reference with its digest set, keeping the tag for
+// readability; the container runtime pulls by digest alone.
+func pinnedImage(image, digest string) string {
+	name, _, _ := strings.Cut(image, "@")
+	return name + "@" + digest
+}
+
+// imageForWebApp returns the image for the pod template and records the resolved digest
+// in the status. Under PinDigest, the tag is resolved again once ImageResolveInterval
+// has passed or Spec.Image changed; if the registry cannot be reached, the previous
+// digest of the same tag is kept.
+func (r *WebAppReconciler) imageForWebApp(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec) (string, error) {
+	if spec.ImagePolicy != appv1alpha1.ImagePolicyPinDigest {
+		webapp.Status.Image = nil
+		return spec.Image, nil
+	}
+	if _, digest, ok := strings.Cut(spec.Image, "@"); ok {
+		webapp.Status.Image = &appv1alpha1.ImageStatus{Tag: spec.Image, Digest: digest}
+		return spec.Image, nil
+	}
+
+	now := r.now()
+	interval := r.ImageResolveInterval
+	if interval <= 0 {
+		interval = DefaultImageResolveInterval
+	}
+	previous := webapp.Status.Image
+	if previous == nil || previous.Tag != spec.Image || previous.Digest == "" {
+		previous = nil
+	}
+	if previous != nil && previous.ResolvedAt != nil && now.Sub(previous.ResolvedAt.Time) < interval {
+		return pinnedImage(spec.Image, previous.Digest), nil
+	}
+
+	resolver := r.ImageResolver
+	if resolver == nil {
+		resolver = &registryResolver{client: registryClient}
+	}
+	credentials, err := r.registryCredentialsForWebApp(ctx, webapp, spec)
+	if err != nil {
+		return "", err
+	}
+	digest, err := resolver.Resolve(ctx, spec.Image, credentials)
+	if err != nil {
+		if previous == nil {
+			return "", &reasonError{reason: "ImageResolveFailed", err: fmt.Errorf("resolving image %s: %w", spec.Image, err)}
+		}
+		logf.FromContext(ctx).Error(err, "keeping the previous digest", "image", spec.Image, "digest", previous.Digest)
+		r.recordEvent(webapp, corev1.EventTypeWarning, "ImageResolveFailed",
+			"keeping digest %s of %s: %v", previous.Digest, spec.Image, err)
+		return pinnedImage(spec.Image, previous.Digest), nil
+	}
+
+	if previous == nil || previous.Digest != digest {
+		r.recordEvent(webapp, corev1.EventTypeNormal, "ImageResolved", "resolved %s to %s", spec.Image, digest)
+	}
+	webapp.Status.Image = &appv1alpha1.ImageStatus{
+		Tag:        spec.Image,
+		Digest:     digest,
+		ResolvedAt: ptr.To(metav1.NewTime(now)),
+	}
+	return pinnedImage(spec.Image, digest), nil
+}
+
+// registryCredentialsForWebApp returns the credentials the kubelet would pull the image
+// of the WebApp with: those of Spec.ImagePullSecrets, then those of the namespace's
+// default ServiceAccount, that match the image's registry. Pull secrets that do not
+// exist are skipped, as the kubelet skips them. Secrets are read through the API reader.
+func (r *WebAppReconciler) registryCredentialsForWebApp(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec) ([]RegistryCredential, error) {
+	ref, err := parseImageReference(spec.Image)
+	if err != nil {
+		return nil, err
+	}
+	refs := slices.Clone(spec.ImagePullSecrets)
+	sa := &corev1.ServiceAccount{}
+	err = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: webapp.Namespace}, sa)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("getting default service account: %w", err)
+	}
+	refs = append(refs, sa.ImagePullSecrets...)
+
+	var credentials []RegistryCredential
+	for _, secretRef := range refs {
+		secret := &corev1.Secret{}
+		err := r.apiReader().Get(ctx, types.NamespacedName{Name: secretRef.Name, Namespace: webapp.Namespace}, secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting image pull secret %s: %w", secretRef.Name, err)
+		}
+		found, err := registryCredentialsFromSecret(secret, ref.host)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, found...)
+	}
+	return credentials, nil
+}
+
+// dockerConfigEntry is the entry for one registry in a Docker config file.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// registryCredentialsFromSecret returns the credentials of a kubernetes.io/dockerconfigjson
+// or kubernetes.io/dockercfg Secret for a registry host. Secrets of other types hold none.
+func registryCredentialsFromSecret(secret *corev1.Secret, host string) ([]RegistryCredential, error) {
+	var entries map[string]dockerConfigEntry
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var config struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("decoding image pull secret %s: %w", secret.Name, err)
+		}
+		entries = config.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &entries); err != nil {
+			return nil, fmt.Errorf("decoding image pull secret %s: %w", secret.Name, err)
+		}
+	default:
+		return nil, nil
+	}
+
+	keys := slices.Sorted(maps.Keys(entries))
+	var credentials []RegistryCredential
+	for _, key := range keys {
+		if !registryKeyMatches(key, host) {
+			continue
+		}
+		entry := entries[key]
+		credential := RegistryCredential{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("decoding auth of %s in image pull secret %s: %w", key, secret.Name, err)
+			}
+			credential.Username, credential.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// dockerHubHosts are the names Docker config files use for Docker Hub.
+var dockerHubHosts = []string{"registry-1.docker.io", "index.docker.io", "docker.io"}
+
+// registryKeyMatches reports whether a Docker config key, such as
+// "https://index.docker.io/v1/" or "*.registry.example.com", names the registry host.
+// A leading "*." matches a single subdomain, as it does for the kubelet.
+func registryKeyMatches(key, host string) bool {
+	if u, err := url.Parse(key); err == nil && u.Host != "" {
+		key = u.Host
+	} else {
+		key, _, _ = strings.Cut(key, "/")
+	}
+	if key == host {
+		return true
+	}
+	if slices.Contains(dockerHubHosts, key) && slices.Contains(dockerHubHosts, host) {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(key, "*."); ok {
+		sub, rest, found := strings.Cut(host, ".")
+		return found && sub != "" && rest == suffix
+	}
+	return false
+}
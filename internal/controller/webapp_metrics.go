@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// Custom metrics exported through the controller-runtime metrics server next to the
+// built-in controller metrics. Every series is labelled with the WebApp's namespace and
+// name, and the series of a WebApp are removed when it is deleted.
+var (
+	// webappReplicasDesired is the replica count of the WebApp's Deployment spec.
+	webappReplicasDesired = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "webapp_replicas_desired",
+		Help: "Replica count requested by the WebApp's Deployment.",
+	}, []string{"namespace", "name"})
+
+	// webappReplicasAvailable is the number of available pods of the WebApp's Deployment.
+	webappReplicasAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "webapp_replicas_available",
+		Help: "Available replicas of the WebApp's Deployment.",
+	}, []string{"namespace", "name"})
+
+	// webappCondition follows the kube-state-metrics convention: one series per condition
+	// and status, set to 1 for the current status and 0 for the others.
+	webappCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "webapp_status_condition",
+		Help: "Status of each WebApp condition; 1 for the current status, 0 otherwise.",
+	}, []string{"namespace", "name", "type", "status"})
+
+	// webappReconcilePhaseDuration times each child resource phase of a reconcile.
+	webappReconcilePhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "webapp_reconcile_phase_duration_seconds",
+		Help:    "Duration of each phase of a WebApp reconcile.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"namespace", "name", "phase"})
+
+	// webappDriftCorrections counts the times drift of the Deployment was reverted.
+	webappDriftCorrections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webapp_drift_corrections_total",
+		Help: "Number of times drift of the WebApp's Deployment was reverted.",
+	}, []string{"namespace", "name"})
+
+	// webappChildOperations counts the child resources created and changed by the operator.
+	// Applies that leave the object unchanged are not counted.
+	webappChildOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webapp_child_operations_total",
+		Help: "Number of child resources created or updated for the WebApp.",
+	}, []string{"namespace", "name", "kind", "operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		webappReplicasDesired,
+		webappReplicasAvailable,
+		webappCondition,
+		webappReconcilePhaseDuration,
+		webappDriftCorrections,
+		webappChildOperations,
+	)
+}
+
+// timePhase runs one reconcile phase and records its duration.
+func timePhase(webapp *appv1alpha1.WebApp, phase string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	webappReconcilePhaseDuration.WithLabelValues(webapp.Namespace, webapp.Name, phase).
+		Observe(time.Since(start).Seconds())
+	return err
+}
+
+// recordConditionMetrics exports the current status of every condition of the WebApp.
+// Series of conditions the WebApp no longer has are removed.
+func recordConditionMetrics(webapp *appv1alpha1.WebApp) {
+	webappCondition.DeletePartialMatch(prometheus.Labels{"namespace": webapp.Namespace, "name": webapp.Name})
+	for _, cond := range webapp.Status.Conditions {
+		for _, status := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown} {
+			value := 0.0
+			if cond.Status == status {
+				value = 1
+			}
+			webappCondition.WithLabelValues(webapp.Namespace, webapp.Name, cond.Type,
+				strings.ToLower(string(status))).Set(value)
+		}
+	}
+}
+
+// recordChildOperation counts a child resource created or changed by the operator.
+func recordChildOperation(webapp *appv1alpha1.WebApp, kind, operation string) {
+	webappChildOperations.WithLabelValues(webapp.Namespace, webapp.Name, kind, operation).Inc()
+}
+
+// deleteWebAppMetrics removes every series of the given WebApp, so deleted WebApps do
+// not linger in dashboards and alerts.
+func deleteWebAppMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	webappReplicasDesired.DeletePartialMatch(labels)
+	webappReplicasAvailable.DeletePartialMatch(labels)
+	webappCondition.DeletePartialMatch(labels)
+	webappReconcilePhaseDuration.DeletePartialMatch(labels)
+	webappDriftCorrections.DeletePartialMatch(labels)
+	webappChildOperations.DeletePartialMatch(labels)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// progressDeadlineExceededReason is the reason the deployment controller sets on the
+// Deployment's Progressing condition once spec.progressDeadlineSeconds has passed
+// without progress.
+const progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// podSecurityViolation is the text the PodSecurity admission plugin puts in the error for
+// a pod it rejects, which the deployment controller copies into the ReplicaFailure
+// condition of the Deployment.
+const podSecurityViolation = "violates PodSecurity"
+
+// rolloutState is the WebApp's view of a Deployment rollout, expressed as the status,
+// reason and message of the Available and Progressing conditions. stuck is set when the
+// rollout stopped making progress, and podSecurityRejection holds the error when Pod
+// Security admission rejects the pods; the WebApp reports both as Degraded.
+type rolloutState struct {
+	available                             metav1.ConditionStatus
+	availableReason, availableMessage     string
+	progressing                           metav1.ConditionStatus
+	progressingReason, progressingMessage string
+	stuck                                 bool
+	podSecurityRejection                  string
+}
+
+// rolloutStateForDeployment derives the rollout state from the Deployment status, the
+// same way `kubectl rollout status` does. The WebApp is only Available once the deployment
+// controller has observed the current generation and every desired replica runs the
+// current pod template and is available, with no old replicas left.
+func rolloutStateForDeployment(dep *appsv1.Deployment) rolloutState {
+	desired := ptr.Deref(dep.Spec.Replicas, 1)
+	status := dep.Status
+	progressing := deploymentCondition(dep, appsv1.DeploymentProgressing)
+	available := deploymentCondition(dep, appsv1.DeploymentAvailable)
+
+	state := rolloutState{
+		available:       metav1.ConditionFalse,
+		availableReason: "RolloutInProgress",
+		availableMessage: fmt.Sprintf("%d of %d replica(s) run the current pod template and are available",
+			min(status.UpdatedReplicas, status.AvailableReplicas), desired),
+		progressing: metav1.ConditionTrue,
+	}
+
+	switch {
+	case dep.Generation > status.ObservedGeneration:
+		state.progressingReason = "RolloutPending"
+		state.progressingMessage = fmt.Sprintf("waiting for the deployment controller to observe generation %d",
+			dep.Generation)
+		state.availableMessage = state.progressingMessage
+	case progressing != nil && progressing.Reason == progressDeadlineExceededReason:
+		state.stuck = true
+		state.availableReason = "RolloutStuck"
+		state.progressing = metav1.ConditionFalse
+		state.progressingReason = progressDeadlineExceededReason
+		state.progressingMessage = progressing.Message
+	case status.UpdatedReplicas < desired:
+		state.progressingReason = "RollingOut"
+		state.progressingMessage = fmt.Sprintf("%d of %d replica(s) updated", status.UpdatedReplicas, desired)
+	case status.Replicas > status.UpdatedReplicas:
+		state.progressingReason = "RollingOut"
+		state.progressingMessage = fmt.Sprintf("%d old replica(s) pending termination",
+			status.Replicas-status.UpdatedReplicas)
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		state.progressingReason = "RollingOut"
+		state.progressingMessage = fmt.Sprintf("%d of %d updated replica(s) available",
+			status.AvailableReplicas, status.UpdatedReplicas)
+	default:
+		state.progressing = metav1.ConditionFalse
+		state.progressingReason = "RolloutComplete"
+		state.progressingMessage = fmt.Sprintf("%d replica(s) run the current pod template", desired)
+		if available == nil || available.Status == corev1.ConditionTrue {
+			state.available = metav1.ConditionTrue
+			state.availableReason = "DeploymentAvailable"
+			state.availableMessage = fmt.Sprintf("%d replica(s) available", status.AvailableReplicas)
+		}
+	}
+
+	if state.available != metav1.ConditionTrue && available != nil && available.Status == corev1.ConditionFalse {
+		state.availableReason = available.Reason
+		state.availableMessage = available.Message
+	}
+	if failure := deploymentCondition(dep, appsv1.DeploymentReplicaFailure); failure != nil &&
+		failure.Status == corev1.ConditionTrue && strings.Contains(failure.Message, podSecurityViolation) {
+		state.podSecurityRejection = failure.Message
+	}
+	return state
+}
+
+// deploymentCondition returns the Deployment condition of the given type, or nil.
+func deploymentCondition(dep *appsv1.Deployment, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range dep.Status.Conditions {
+		if dep.Status.Conditions[i].Type == condType {
+			return &dep.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+	"github.com/54b3r/platform-operator-blueprint/internal/schedule"
+)
+
+// scheduleStatusForWebApp works out which of the WebApp's replica schedules is in effect
+// at now, the one that fired last, and which fires next. It returns nil without schedules.
+// Schedules that fire at the same time are ordered as listed, the last one winning.
+func scheduleStatusForWebApp(schedules []appv1alpha1.ReplicaSchedule, now time.Time) (*appv1alpha1.ScheduleStatus, error) {
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	status := &appv1alpha1.ScheduleStatus{}
+	var last, next time.Time
+	for _, s := range schedules {
+		loc, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return nil, &reasonError{reason: "InvalidSchedule", err: fmt.Errorf("schedule %s: %w", s.Name, err)}
+		}
+		parsed, err := schedule.Parse(s.Cron, loc)
+		if err != nil {
+			return nil, &reasonError{reason: "InvalidSchedule", err: fmt.Errorf("schedule %s: %w", s.Name, err)}
+		}
+
+		if prev, ok := parsed.Last(now); ok && !prev.Before(last) {
+			last = prev
+			status.Active = s.Name
+			status.Replicas = ptr.To(s.Replicas)
+			status.LastTriggerTime = ptr.To(metav1.NewTime(prev))
+		}
+		if after := parsed.Next(now); !after.IsZero() && (next.IsZero() || after.Before(next)) {
+			next = after
+			status.Next = s.Name
+			status.NextTriggerTime = ptr.To(metav1.NewTime(after))
+		}
+	}
+	return status, nil
+}
+
+// reconcileSchedule records the replica schedule in effect in the status, which
+// reconcileDeployment reads the replica count from, and emits an event when another
+// schedule has fired since the last reconcile.
+func (r *WebAppReconciler) reconcileSchedule(webapp *appv1alpha1.WebApp) error {
+	status, err := scheduleStatusForWebApp(webapp.Spec.Schedules, r.now())
+	if err != nil {
+		return err
+	}
+	previous := webapp.Status.Schedule
+	webapp.Status.Schedule = status
+	if status == nil || status.Active == "" {
+		return nil
+	}
+	if previous == nil || previous.Active != status.Active || !previous.LastTriggerTime.Equal(status.LastTriggerTime) {
+		r.recordEvent(webapp, corev1.EventTypeNormal, "ScheduleActivated",
+			"schedule %s set the replica count to %d", status.Active, *status.Replicas)
+	}
+	return nil
+}
+
+// scheduledReplicas returns the replica count of the active replica schedule, or nil when
+// none has fired.
+func scheduledReplicas(webapp *appv1alpha1.WebApp) *int32 {
+	if st := webapp.Status.Schedule; st != nil && st.Active != "" {
+		return st.Replicas
+	}
+	return nil
+}
+
+// scheduleRequeueAfter returns how long until the next replica schedule fires, or zero
+// when none does.
+func scheduleRequeueAfter(webapp *appv1alpha1.WebApp, now time.Time) time.Duration {
+	st := webapp.Status.Schedule
+	if st == nil || st.NextTriggerTime == nil {
+		return 0
+	}
+	return max(st.NextTriggerTime.Sub(now), time.Second)
+}
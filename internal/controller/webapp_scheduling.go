@@ -0,0 +1,52 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// zoneSpreadTopologyKeys are the node labels the "zone" spread preset spreads the pods
+// across: first zones, then nodes within a zone.
+var zoneSpreadTopologyKeys = []string{corev1.LabelTopologyZone, corev1.LabelHostname}
+
+// topologySpreadConstraintsForWebApp returns the WebApp's topology spread constraints
+// followed by those of its Spread preset. The preset prefers an even spread but never
+// keeps a pod from being scheduled, so it also works on clusters whose nodes carry no
+// zone label. A preset constraint is left out when the spec has one with its topology key.
+func topologySpreadConstraintsForWebApp(name string, spec *appv1alpha1.WebAppSpec) []corev1.TopologySpreadConstraint {
+	constraints := slices.Clone(spec.TopologySpreadConstraints)
+	if spec.Spread != appv1alpha1.SpreadZone {
+		return constraints
+	}
+	for _, key := range zoneSpreadTopologyKeys {
+		if slices.ContainsFunc(constraints, func(c corev1.TopologySpreadConstraint) bool { return c.TopologyKey == key }) {
+			continue
+		}
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     mainPodSelectorForWebApp(name),
+		})
+	}
+	return constraints
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// applySecurityContext sets the security contexts of the pod and of each of its
+// containers, init containers and sidecars included. Without Spec.SecurityContext the
+// Restricted profile applies: the pod runs as non-root with the RuntimeDefault seccomp
+// profile, and every container drops all capabilities and cannot escalate privileges.
+// The profile None sets only the fields given in the spec.
+func applySecurityContext(pod *corev1.PodSpec, spec *appv1alpha1.WebAppSpec) {
+	sc := ptr.Deref(spec.SecurityContext, appv1alpha1.SecurityContextSpec{})
+	restricted := sc.Profile != appv1alpha1.SecurityProfileNone
+
+	if restricted || sc.RunAsUser != nil || sc.RunAsGroup != nil || sc.FSGroup != nil {
+		pod.SecurityContext = &corev1.PodSecurityContext{
+			RunAsUser:  sc.RunAsUser,
+			RunAsGroup: sc.RunAsGroup,
+			FSGroup:    sc.FSGroup,
+		}
+		if restricted {
+			pod.SecurityContext.RunAsNonRoot = ptr.To(true)
+			pod.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+		}
+	}
+
+	if !restricted && !sc.ReadOnlyRootFilesystem && len(sc.AddCapabilities) == 0 {
+		return
+	}
+	for _, containers := range [][]corev1.Container{pod.InitContainers, pod.Containers} {
+		for i := range containers {
+			container := &corev1.SecurityContext{}
+			if restricted {
+				container.AllowPrivilegeEscalation = ptr.To(false)
+				container.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+			}
+			if sc.ReadOnlyRootFilesystem {
+				container.ReadOnlyRootFilesystem = ptr.To(true)
+			}
+			if len(sc.AddCapabilities) > 0 {
+				if container.Capabilities == nil {
+					container.Capabilities = &corev1.Capabilities{}
+				}
+				container.Capabilities.Add = slices.Clone(sc.AddCapabilities)
+			}
+			containers[i].SecurityContext = container
+		}
+	}
+}
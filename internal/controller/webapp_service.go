@@ -0,0 +1,173 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// serviceAppProtocols maps the application protocols of the WebApp API to the appProtocol
+// values of a Service port that ingress controllers and Gateways recognize.
+var serviceAppProtocols = map[appv1alpha1.AppProtocol]string{
+	appv1alpha1.AppProtocolHTTP: "http",
+	appv1alpha1.AppProtocolH2C:  "kubernetes.io/h2c",
+	appv1alpha1.AppProtocolGRPC: "grpc",
+}
+
+// portsForWebApp returns the ports of the main container: Spec.Ports, or Spec.Port as a
+// single TCP port named "http". The spec is expected to be defaulted already.
+func portsForWebApp(spec *appv1alpha1.WebAppSpec) []appv1alpha1.PortSpec {
+	if len(spec.Ports) > 0 {
+		return spec.Ports
+	}
+	return []appv1alpha1.PortSpec{{
+		Name:          appv1alpha1.DefaultPortName,
+		ContainerPort: spec.Port,
+		Protocol:      corev1.ProtocolTCP,
+	}}
+}
+
+// containerPortsForWebApp returns the ports of the main container. Spec.Port stays an
+// unnamed container port, so upgrading the operator does not roll the pods.
+func containerPortsForWebApp(spec *appv1alpha1.WebAppSpec) []corev1.ContainerPort {
+	if len(spec.Ports) == 0 {
+		return []corev1.ContainerPort{{ContainerPort: spec.Port, Protocol: corev1.ProtocolTCP}}
+	}
+	out := make([]corev1.ContainerPort, 0, len(spec.Ports))
+	for _, p := range spec.Ports {
+		out = append(out, corev1.ContainerPort{Name: p.Name, ContainerPort: p.ContainerPort, Protocol: p.Protocol})
+	}
+	return out
+}
+
+// servicePortsForWebApp returns the Service ports, each targeting its container port.
+func servicePortsForWebApp(spec *appv1alpha1.WebAppSpec) []corev1.ServicePort {
+	var out []corev1.ServicePort
+	for _, p := range portsForWebApp(spec) {
+		port := corev1.ServicePort{
+			Name:       p.Name,
+			Port:       cmp.Or(p.ServicePort, p.ContainerPort),
+			TargetPort: intstr.FromInt32(p.ContainerPort),
+			Protocol:   p.Protocol,
+		}
+		if p.AppProtocol != nil {
+			port.AppProtocol = ptr.To(serviceAppProtocols[*p.AppProtocol])
+		}
+		out = append(out, port)
+	}
+	return out
+}
+
+// primaryServicePort returns the Service port of the first port, which Ingresses and
+// HTTPRoutes route to.
+func primaryServicePort(spec *appv1alpha1.WebAppSpec) int32 {
+	first := portsForWebApp(spec)[0]
+	return cmp.Or(first.ServicePort, first.ContainerPort)
+}
+
+// reconcileService server-side applies the Service for the given WebApp, of the type set
+// by Spec.Service. It sets an owner reference so the Service is garbage-collected with
+// the WebApp. The ClusterIP and node ports are allocated by the API server and never
+// applied. Since the ClusterIP cannot change, switching to or from a headless Service
+// deletes the existing Service first.
+func (r *WebAppReconciler) reconcileService(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	desired := serviceForWebApp(webapp, webapp.Name, serviceSelectorForWebApp(webapp))
+	if opts := webapp.Spec.Service; opts != nil {
+		desired.Annotations = opts.Annotations
+		switch opts.Type {
+		case appv1alpha1.ServiceTypeHeadless:
+			desired.Spec.ClusterIP = corev1.ClusterIPNone
+		case appv1alpha1.ServiceTypeNodePort, appv1alpha1.ServiceTypeLoadBalancer:
+			desired.Spec.Type = corev1.ServiceType(opts.Type)
+			desired.Spec.ExternalTrafficPolicy = opts.ExternalTrafficPolicy
+		}
+	}
+
+	existing := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, existing)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return fmt.Errorf("getting service: %w", err)
+	case (existing.Spec.ClusterIP == corev1.ClusterIPNone) != (desired.Spec.ClusterIP == corev1.ClusterIPNone):
+		logf.FromContext(ctx).Info("recreating service to change its cluster IP", "name", existing.Name)
+		if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting service: %w", err)
+		}
+		recordChildOperation(webapp, "service", "delete")
+		r.recordEvent(webapp, corev1.EventTypeNormal, "ServiceRecreated",
+			"recreating service %s to switch to or from a headless service", existing.Name)
+	}
+	return r.apply(ctx, webapp, desired, "service")
+}
+
+// serviceForWebApp builds a ClusterIP Service with the given name that routes the WebApp
+// ports to the pods matching selector.
+func serviceForWebApp(webapp *appv1alpha1.WebApp, name string, selector map[string]string) *corev1.Service {
+	spec := webapp.Spec.DeepCopy()
+	spec.ApplyDefaults()
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: webapp.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports:    servicePortsForWebApp(spec),
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// updateServiceStatus records the type and addresses of the Service in the status, and
+// emits an event once the load balancer of a LoadBalancer Service has an address.
+func (r *WebAppReconciler) updateServiceStatus(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, svc); err != nil {
+		return fmt.Errorf("fetching service for status: %w", err)
+	}
+
+	status := &appv1alpha1.ServiceStatus{Type: appv1alpha1.ServiceType(svc.Spec.Type), ClusterIP: svc.Spec.ClusterIP}
+	if svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		status.Type, status.ClusterIP = appv1alpha1.ServiceTypeHeadless, ""
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if address := cmp.Or(ingress.IP, ingress.Hostname); address != "" {
+			status.LoadBalancerIngress = append(status.LoadBalancerIngress, address)
+		}
+	}
+
+	if previous := webapp.Status.Service; len(status.LoadBalancerIngress) > 0 &&
+		(previous == nil || len(previous.LoadBalancerIngress) == 0) {
+		r.recordEvent(webapp, corev1.EventTypeNormal, "LoadBalancerReady",
+			"load balancer of service %s is reachable at %s", svc.Name, status.LoadBalancerIngress[0])
+	}
+	webapp.Status.Service = status
+	return nil
+}
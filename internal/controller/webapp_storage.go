@@ -0,0 +1,307 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// storageVolume is a persistent volume of a WebApp together with the name of the
+// PersistentVolumeClaim that backs it.
+type storageVolume struct {
+	appv1alpha1.VolumeSpec
+	ClaimName string
+}
+
+// storageVolumesForWebApp returns every persistent volume of the WebApp in a single form.
+// The Spec.Storage shorthand becomes a ReadWriteOnce volume named "data", backed by claim
+// "<webapp>-pvc" and mounted at its MountPath, /data by default; each entry of
+// Spec.Volumes is backed by claim "<webapp>-<volume>". Entries without access modes
+// default to ReadWriteOnce.
+func storageVolumesForWebApp(name string, spec *appv1alpha1.WebAppSpec) []storageVolume {
+	var volumes []storageVolume
+	if spec.Storage != nil {
+		volumes = append(volumes, storageVolume{
+			VolumeSpec: appv1alpha1.VolumeSpec{
+				Name:             appv1alpha1.StorageVolumeName,
+				Size:             spec.Storage.Size,
+				StorageClassName: spec.Storage.StorageClassName,
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				MountPath:        cmp.Or(spec.Storage.MountPath, appv1alpha1.DefaultMountPath),
+			},
+			ClaimName: name + "-pvc",
+		})
+	}
+	for _, v := range spec.Volumes {
+		if len(v.AccessModes) == 0 {
+			v.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
+		volumes = append(volumes, storageVolume{VolumeSpec: v, ClaimName: name + "-" + v.Name})
+	}
+	return volumes
+}
+
+// isBlockVolume reports whether the volume is exposed to the container as a raw block device.
+func isBlockVolume(v storageVolume) bool {
+	return ptr.Deref(v.VolumeMode, corev1.PersistentVolumeFilesystem) == corev1.PersistentVolumeBlock
+}
+
+// reconcileStorage creates or updates the PersistentVolumeClaim of every persistent
+// volume of the given WebApp. Claims of volumes removed from the spec are left in place
+// so that their data is not lost; they are garbage-collected with the WebApp.
+func (r *WebAppReconciler) reconcileStorage(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	for _, volume := range storageVolumesForWebApp(webapp.Name, &webapp.Spec) {
+		if err := r.reconcileClaim(ctx, webapp, volume); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileClaim server-side applies the PersistentVolumeClaim for one volume, growing it
+// when the requested size increased. It sets an owner reference so the PVC is
+// garbage-collected with the WebApp.
+func (r *WebAppReconciler) reconcileClaim(ctx context.Context, webapp *appv1alpha1.WebApp, volume storageVolume) error {
+	log := logf.FromContext(ctx)
+
+	desiredPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      volume.ClaimName,
+			Namespace: webapp.Namespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      volume.AccessModes,
+			VolumeMode:       volume.VolumeMode,
+			StorageClassName: volume.StorageClassName,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: volume.Size,
+				},
+			},
+		},
+	}
+
+	// PVC spec is immutable after creation, except that the storage request may grow
+	// when the StorageClass supports volume expansion.
+	existing := &corev1.PersistentVolumeClaim{}
+	err := r.Get(ctx, types.NamespacedName{Name: volume.ClaimName, Namespace: webapp.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("creating pvc", "name", volume.ClaimName)
+		return r.apply(ctx, webapp, desiredPVC, "pvc")
+	}
+	if err != nil {
+		return fmt.Errorf("getting pvc %s: %w", volume.ClaimName, err)
+	}
+	// Keep applying the immutable fields the claim was created with, so that a changed
+	// access mode, volume mode or class in the spec never turns into a rejected apply.
+	desiredPVC.Spec.AccessModes = existing.Spec.AccessModes
+	desiredPVC.Spec.VolumeMode = existing.Spec.VolumeMode
+	desiredPVC.Spec.StorageClassName = existing.Spec.StorageClassName
+
+	current := existing.Spec.Resources.Requests[corev1.ResourceStorage]
+	switch volume.Size.Cmp(current) {
+	case 0:
+		// PVC already has the requested size — no update needed.
+		return nil
+	case -1:
+		// The validating webhook rejects this; it can still happen with webhooks disabled.
+		return &reasonError{reason: "StorageShrinkRejected", err: fmt.Errorf(
+			"size %s of volume %s is smaller than the current %s: volumes cannot shrink",
+			volume.Size.String(), volume.Name, current.String())}
+	}
+
+	className := ptr.Deref(existing.Spec.StorageClassName, "")
+	expandable, err := r.storageClassAllowsExpansion(ctx, className)
+	if err != nil {
+		return err
+	}
+	if !expandable {
+		return &reasonError{reason: "StorageExpansionNotSupported", err: fmt.Errorf(
+			"cannot grow pvc %s to %s: storage class %q does not allow volume expansion",
+			existing.Name, volume.Size.String(), className)}
+	}
+
+	log.Info("expanding pvc", "name", existing.Name, "from", current.String(), "to", volume.Size.String())
+	return r.apply(ctx, webapp, desiredPVC, "pvc")
+}
+
+// storageClassAllowsExpansion reports whether the named StorageClass sets
+// allowVolumeExpansion. A claim without a class, or with a class that no longer
+// exists, cannot be expanded.
+func (r *WebAppReconciler) storageClassAllowsExpansion(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+	class := &storagev1.StorageClass{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, class)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting storage class %s: %w", name, err)
+	}
+	return ptr.Deref(class.AllowVolumeExpansion, false), nil
+}
+
+// updateStorageStatus reports the capacity of every claim and mirrors their resize
+// progress into the FileSystemResizePending condition, which is True while any claim
+// waits for a file system resize. Both are cleared when no storage is configured.
+func (r *WebAppReconciler) updateStorageStatus(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	volumes := storageVolumesForWebApp(webapp.Name, &webapp.Spec)
+	if len(volumes) == 0 {
+		webapp.Status.Storage = nil
+		meta.RemoveStatusCondition(&webapp.Status.Conditions, appv1alpha1.TypeFileSystemResizePending)
+		return nil
+	}
+
+	webapp.Status.Storage = &appv1alpha1.StorageStatus{}
+	var pending, resizing []string
+	for _, volume := range volumes {
+		pvc := &corev1.PersistentVolumeClaim{}
+		key := types.NamespacedName{Name: volume.ClaimName, Namespace: webapp.Namespace}
+		if err := r.Get(ctx, key, pvc); err != nil {
+			return fmt.Errorf("fetching pvc %s for status: %w", volume.ClaimName, err)
+		}
+
+		var capacity *resource.Quantity
+		if c, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			capacity = &c
+		}
+		if webapp.Spec.Storage != nil && volume.Name == appv1alpha1.StorageVolumeName {
+			webapp.Status.Storage.Capacity = capacity
+		} else {
+			webapp.Status.Storage.Volumes = append(webapp.Status.Storage.Volumes, appv1alpha1.VolumeStatus{
+				Name: volume.Name, ClaimName: volume.ClaimName, Capacity: capacity,
+			})
+		}
+
+		for _, cond := range pvc.Status.Conditions {
+			if cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch cond.Type {
+			case corev1.PersistentVolumeClaimFileSystemResizePending:
+				pending = append(pending, volume.Name)
+			case corev1.PersistentVolumeClaimResizing:
+				resizing = append(resizing, volume.Name)
+			}
+		}
+	}
+
+	status, reason, message := metav1.ConditionFalse, "NoResizePending", "no volume resize in progress"
+	switch {
+	case len(pending) > 0:
+		status, reason = metav1.ConditionTrue, "FileSystemResizePending"
+		message = fmt.Sprintf("volume(s) %s expanded; the file system is resized when a pod using it (re)starts",
+			strings.Join(pending, ", "))
+	case len(resizing) > 0:
+		reason = "VolumeResizing"
+		message = fmt.Sprintf("the storage backend is expanding volume(s) %s", strings.Join(resizing, ", "))
+	}
+	setCondition(webapp, appv1alpha1.TypeFileSystemResizePending, status, reason, message)
+	return nil
+}
+
+// podVolumesForWebApp returns the pod volumes that reference the claims of the given
+// persistent volumes. Returns nil if no storage is configured.
+func podVolumesForWebApp(volumes []storageVolume) []corev1.Volume {
+	var out []corev1.Volume
+	for _, v := range volumes {
+		out = append(out, corev1.Volume{
+			Name: v.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: v.ClaimName,
+					ReadOnly:  v.ReadOnly,
+				},
+			},
+		})
+	}
+	return out
+}
+
+// volumeMountsForWebApp returns the VolumeMounts of the main container for every
+// file system volume. Returns nil if there is none.
+func volumeMountsForWebApp(volumes []storageVolume) []corev1.VolumeMount {
+	var out []corev1.VolumeMount
+	for _, v := range volumes {
+		if isBlockVolume(v) {
+			continue
+		}
+		out = append(out, corev1.VolumeMount{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			SubPath:   v.SubPath,
+			ReadOnly:  v.ReadOnly,
+		})
+	}
+	return out
+}
+
+// scratchPodVolumesForWebApp returns the emptyDir pod volumes of Spec.ScratchVolumes.
+// Returns nil if there is none.
+func scratchPodVolumesForWebApp(spec *appv1alpha1.WebAppSpec) []corev1.Volume {
+	var out []corev1.Volume
+	for _, v := range spec.ScratchVolumes {
+		out = append(out, corev1.Volume{
+			Name: v.Name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: v.Medium, SizeLimit: v.SizeLimit},
+			},
+		})
+	}
+	return out
+}
+
+// scratchVolumeMountsForWebApp returns the VolumeMounts of the main container for every
+// scratch volume with a mount path. Returns nil if there is none.
+func scratchVolumeMountsForWebApp(spec *appv1alpha1.WebAppSpec) []corev1.VolumeMount {
+	var out []corev1.VolumeMount
+	for _, v := range spec.ScratchVolumes {
+		if v.MountPath != "" {
+			out = append(out, corev1.VolumeMount{Name: v.Name, MountPath: v.MountPath})
+		}
+	}
+	return out
+}
+
+// volumeDevicesForWebApp returns the VolumeDevices of the main container for every
+// block volume, using the mount path as the device path. Returns nil if there is none.
+func volumeDevicesForWebApp(volumes []storageVolume) []corev1.VolumeDevice {
+	var out []corev1.VolumeDevice
+	for _, v := range volumes {
+		if isBlockVolume(v) {
+			out = append(out, corev1.VolumeDevice{Name: v.Name, DevicePath: v.MountPath})
+		}
+	}
+	return out
+}
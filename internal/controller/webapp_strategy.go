@@ -0,0 +1,421 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// templateHashAnnotation is set on the managed Deployments to a hash of the pod template
+// the operator applied. Canary and BlueGreen rollouts start when the desired pod template
+// no longer matches the one of the main Deployment.
+const templateHashAnnotation = "app.54b3r.io/template-hash"
+
+// rolloutTrackLabel marks the pods of the canary Deployment. Canary pods also carry the
+// WebApp labels, so the main Service sends them their share of the traffic. Only the
+// canary Deployment sets it: the selector of the main Deployment cannot change once
+// created, so whatever must leave canary pods out selects on its value instead.
+const rolloutTrackLabel = "app.54b3r.io/track"
+
+// trackCanary is the value of rolloutTrackLabel on canary pods.
+const trackCanary = "canary"
+
+// previewOfLabel marks the pods of a BlueGreen preview Deployment with the WebApp name.
+// Preview pods lack the instance label, so the main Service only reaches them once the
+// rollout is promoted and its selector is switched.
+const previewOfLabel = "app.54b3r.io/preview-of"
+
+// canaryName returns the name of the canary Deployment of a WebApp.
+func canaryName(name string) string { return name + "-canary" }
+
+// previewName returns the name of the preview Deployment and Service of a WebApp.
+func previewName(name string) string { return name + "-preview" }
+
+// mainPodSelectorForWebApp selects the pods of the main Deployment: those with the WebApp
+// labels that are not canary pods.
+func mainPodSelectorForWebApp(name string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: labelsForWebApp(name),
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      rolloutTrackLabel,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   []string{trackCanary},
+		}},
+	}
+}
+
+// canaryLabelsForWebApp returns the labels of the canary pods.
+func canaryLabelsForWebApp(name string) map[string]string {
+	labels := labelsForWebApp(name)
+	labels[rolloutTrackLabel] = trackCanary
+	return labels
+}
+
+// previewLabelsForWebApp returns the labels of the BlueGreen preview pods.
+func previewLabelsForWebApp(name string) map[string]string {
+	labels := labelsForWebApp(name)
+	delete(labels, "app.kubernetes.io/instance")
+	labels[previewOfLabel] = name
+	return labels
+}
+
+// rolloutStrategyForWebApp returns the rollout strategy of a defaulted spec.
+func rolloutStrategyForWebApp(spec *appv1alpha1.WebAppSpec) appv1alpha1.RolloutStrategy {
+	if spec.Rollout == nil {
+		return appv1alpha1.RolloutStrategyRollingUpdate
+	}
+	return spec.Rollout.Strategy
+}
+
+// templateHashForDeployment hashes the pod template of the desired Deployment.
+func templateHashForDeployment(desired *appsv1.Deployment) (string, error) {
+	data, err := json.Marshal(desired.Spec.Template)
+	if err != nil {
+		return "", fmt.Errorf("hashing pod template: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// takeRolloutAction removes RolloutActionAnnotation from the WebApp and returns its value,
+// so every promote or abort is acted on exactly once.
+func (r *WebAppReconciler) takeRolloutAction(ctx context.Context, webapp *appv1alpha1.WebApp) (string, error) {
+	action, ok := webapp.Annotations[appv1alpha1.RolloutActionAnnotation]
+	if !ok {
+		return "", nil
+	}
+	patch := client.MergeFrom(webapp.DeepCopy())
+	delete(webapp.Annotations, appv1alpha1.RolloutActionAnnotation)
+	if err := r.Patch(ctx, webapp, patch); err != nil {
+		return "", fmt.Errorf("removing rollout action annotation: %w", err)
+	}
+	if action != appv1alpha1.RolloutActionPromote && action != appv1alpha1.RolloutActionAbort {
+		r.recordEvent(webapp, corev1.EventTypeWarning, "UnknownRolloutAction",
+			"ignored rollout action %q, expected %q or %q", action,
+			appv1alpha1.RolloutActionPromote, appv1alpha1.RolloutActionAbort)
+		return "", nil
+	}
+	return action, nil
+}
+
+// reconcileProgressiveRollout rolls out a new pod template with the Canary or BlueGreen
+// strategy. The main Deployment keeps the previous pod template until the rollout is
+// promoted; existing is the main Deployment and desired the Deployment to promote to.
+func (r *WebAppReconciler) reconcileProgressiveRollout(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec, desired, existing *appsv1.Deployment, action string) error {
+	revision := desired.Annotations[templateHashAnnotation]
+	st := webapp.Status.Rollout
+	if st == nil || st.Revision != revision || st.Strategy != spec.Rollout.Strategy {
+		st = &appv1alpha1.RolloutStatus{
+			Strategy: spec.Rollout.Strategy,
+			Phase:    appv1alpha1.RolloutPhaseProgressing,
+			Revision: revision,
+		}
+		webapp.Status.Rollout = st
+		r.recordEvent(webapp, corev1.EventTypeNormal, "RolloutStarted",
+			"started %s rollout of revision %s", st.Strategy, shortRevision(revision))
+	}
+
+	switch {
+	case st.Phase == appv1alpha1.RolloutPhasePromoting:
+		// The promotion was decided but applying the main Deployment failed; retry it.
+		return r.applyDeployment(ctx, webapp, spec.DriftPolicy, desired, existing, spec.Autoscaling != nil)
+	case st.Phase == appv1alpha1.RolloutPhaseAborted:
+		return r.applyStableDeployment(ctx, webapp, desired, existing, desired.Spec.Replicas)
+	case action == appv1alpha1.RolloutActionAbort:
+		st.Phase = appv1alpha1.RolloutPhaseAborted
+		st.Weight = 0
+		st.StepStartedAt = nil
+		st.Message = "aborted; the main deployment keeps the previous pod template until the spec changes"
+		r.recordEvent(webapp, corev1.EventTypeWarning, "RolloutAborted",
+			"aborted %s rollout of revision %s", st.Strategy, shortRevision(revision))
+		if err := r.deleteRolloutObjects(ctx, webapp); err != nil {
+			return err
+		}
+		return r.applyStableDeployment(ctx, webapp, desired, existing, desired.Spec.Replicas)
+	case st.Strategy == appv1alpha1.RolloutStrategyCanary:
+		return r.reconcileCanary(ctx, webapp, spec, desired, existing, action == appv1alpha1.RolloutActionPromote)
+	default:
+		return r.reconcileBlueGreen(ctx, webapp, spec, desired, existing, action == appv1alpha1.RolloutActionPromote)
+	}
+}
+
+// reconcileCanary runs the current Canary step: the canary Deployment gets the step's share
+// of the replicas and, without autoscaling, the main Deployment gives them up, keeping at
+// least one. Once the last step ends, the new pod template is applied to the main Deployment.
+func (r *WebAppReconciler) reconcileCanary(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec, desired, existing *appsv1.Deployment, promote bool) error {
+	st := webapp.Status.Rollout
+	steps := spec.Rollout.Steps
+	if int(st.CurrentStep) < len(steps) {
+		step := steps[st.CurrentStep]
+		st.Weight = step.Weight
+
+		canaryReplicas, stable := canaryStepReplicas(ptr.Deref(desired.Spec.Replicas, 1), step.Weight)
+		stableReplicas := desired.Spec.Replicas
+		if spec.Autoscaling == nil {
+			stableReplicas = ptr.To(stable)
+		}
+
+		canary := rolloutDeployment(desired, canaryName(webapp.Name), canaryLabelsForWebApp(webapp.Name), canaryReplicas)
+		if err := r.apply(ctx, webapp, canary, "canary deployment"); err != nil {
+			return err
+		}
+		if err := r.applyStableDeployment(ctx, webapp, desired, existing, stableReplicas); err != nil {
+			return err
+		}
+
+		ready := rolloutStateForDeployment(canary).available == metav1.ConditionTrue
+		if !endRolloutStep(st, ready, step.Pause, promote, r.now()) {
+			return nil
+		}
+		st.CurrentStep++
+		if int(st.CurrentStep) < len(steps) {
+			st.Phase = appv1alpha1.RolloutPhaseProgressing
+			st.StepStartedAt = nil
+			st.Message = fmt.Sprintf("moving to step %d of %d", st.CurrentStep+1, len(steps))
+			r.recordEvent(webapp, corev1.EventTypeNormal, "RolloutStepCompleted",
+				"completed canary step %d of %d at weight %d%%", st.CurrentStep, len(steps), step.Weight)
+			return nil
+		}
+	}
+
+	st.Phase = appv1alpha1.RolloutPhasePromoting
+	st.Weight = 100
+	st.StepStartedAt = nil
+	st.Message = "rolling out the new pod template to the main deployment"
+	r.recordEvent(webapp, corev1.EventTypeNormal, "RolloutPromoted",
+		"promoted revision %s to the main deployment", shortRevision(st.Revision))
+	return r.applyDeployment(ctx, webapp, spec.DriftPolicy, desired, existing, spec.Autoscaling != nil)
+}
+
+// reconcileBlueGreen runs the preview of a BlueGreen rollout: a full-size preview
+// Deployment reachable through the preview Service. Once promoted, the main Service is
+// switched to the preview pods while the main Deployment rolls out the new pod template.
+func (r *WebAppReconciler) reconcileBlueGreen(ctx context.Context, webapp *appv1alpha1.WebApp,
+	spec *appv1alpha1.WebAppSpec, desired, existing *appsv1.Deployment, promote bool) error {
+	st := webapp.Status.Rollout
+	st.Weight = 0
+
+	labels := previewLabelsForWebApp(webapp.Name)
+	preview := rolloutDeployment(desired, previewName(webapp.Name), labels, ptr.Deref(desired.Spec.Replicas, 1))
+	if err := r.apply(ctx, webapp, preview, "preview deployment"); err != nil {
+		return err
+	}
+	if err := r.apply(ctx, webapp, serviceForWebApp(webapp, previewName(webapp.Name), labels), "preview service"); err != nil {
+		return err
+	}
+	if err := r.applyStableDeployment(ctx, webapp, desired, existing, desired.Spec.Replicas); err != nil {
+		return err
+	}
+
+	ready := rolloutStateForDeployment(preview).available == metav1.ConditionTrue
+	if !endRolloutStep(st, ready, spec.Rollout.PreviewPause, promote, r.now()) {
+		return nil
+	}
+
+	st.Phase = appv1alpha1.RolloutPhasePromoting
+	st.Weight = 100
+	st.StepStartedAt = nil
+	st.Message = "the service points at the preview while the main deployment rolls out the new pod template"
+	r.recordEvent(webapp, corev1.EventTypeNormal, "RolloutPromoted",
+		"switched the service to revision %s", shortRevision(st.Revision))
+	return r.applyDeployment(ctx, webapp, spec.DriftPolicy, desired, existing, spec.Autoscaling != nil)
+}
+
+// endRolloutStep reports whether the current step or preview may end: it was promoted, or
+// its replicas are available and its pause has passed. The pause counts from the moment
+// the replicas became available; without a pause the step waits to be promoted.
+func endRolloutStep(st *appv1alpha1.RolloutStatus, ready bool, pause *metav1.Duration, promote bool, now time.Time) bool {
+	if promote {
+		return true
+	}
+	if !ready {
+		st.Phase = appv1alpha1.RolloutPhaseProgressing
+		st.StepStartedAt = nil
+		st.Message = "waiting for the new replicas to become available"
+		return false
+	}
+	if st.Phase != appv1alpha1.RolloutPhasePaused || st.StepStartedAt == nil {
+		st.Phase = appv1alpha1.RolloutPhasePaused
+		st.StepStartedAt = ptr.To(metav1.NewTime(now))
+	}
+	if pause == nil {
+		st.Message = fmt.Sprintf("waiting to be promoted with the %s=%s annotation",
+			appv1alpha1.RolloutActionAnnotation, appv1alpha1.RolloutActionPromote)
+		return false
+	}
+	until := st.StepStartedAt.Add(pause.Duration)
+	if !now.Before(until) {
+		return true
+	}
+	st.Message = "pausing until " + until.UTC().Format(time.RFC3339)
+	return false
+}
+
+// rolloutRequeueAfter returns how long until the pause of the current rollout step ends,
+// or zero when the rollout does not wait for a pause.
+func rolloutRequeueAfter(webapp *appv1alpha1.WebApp, now time.Time) time.Duration {
+	st := webapp.Status.Rollout
+	rollout := webapp.Spec.Rollout
+	if st == nil || rollout == nil || st.Phase != appv1alpha1.RolloutPhasePaused || st.StepStartedAt == nil {
+		return 0
+	}
+	pause := rollout.PreviewPause
+	if st.Strategy == appv1alpha1.RolloutStrategyCanary {
+		if int(st.CurrentStep) >= len(rollout.Steps) {
+			return 0
+		}
+		pause = rollout.Steps[st.CurrentStep].Pause
+	}
+	if pause == nil {
+		return 0
+	}
+	return max(st.StepStartedAt.Add(pause.Duration).Sub(now), time.Second)
+}
+
+// finishRollout runs after the main Deployment was applied with the desired pod template.
+// A promoted rollout completes once the main Deployment is available; the canary or
+// preview objects are removed on the following reconcile, after the main Service
+// selects the main pods again.
+func (r *WebAppReconciler) finishRollout(ctx context.Context, webapp *appv1alpha1.WebApp,
+	strategy appv1alpha1.RolloutStrategy, revision string) error {
+	if strategy == appv1alpha1.RolloutStrategyRollingUpdate {
+		webapp.Status.Rollout = nil
+		return r.deleteRolloutObjects(ctx, webapp)
+	}
+
+	st := webapp.Status.Rollout
+	if st != nil && st.Revision == revision && st.Phase == appv1alpha1.RolloutPhasePromoting {
+		dep := &appsv1.Deployment{}
+		if err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, dep); err != nil {
+			return fmt.Errorf("getting deployment: %w", err)
+		}
+		if rolloutStateForDeployment(dep).available != metav1.ConditionTrue {
+			return nil
+		}
+		st.Phase = appv1alpha1.RolloutPhaseCompleted
+		st.Message = "the main deployment runs the new pod template"
+		r.recordEvent(webapp, corev1.EventTypeNormal, "RolloutCompleted",
+			"completed %s rollout of revision %s", st.Strategy, shortRevision(revision))
+		return nil
+	}
+	if st == nil || st.Revision != revision || st.Strategy != strategy || st.Phase != appv1alpha1.RolloutPhaseCompleted {
+		webapp.Status.Rollout = &appv1alpha1.RolloutStatus{
+			Strategy: strategy,
+			Phase:    appv1alpha1.RolloutPhaseCompleted,
+			Revision: revision,
+			Weight:   100,
+			Message:  "the main deployment runs the current pod template",
+		}
+	}
+	return r.deleteRolloutObjects(ctx, webapp)
+}
+
+// applyStableDeployment applies the main Deployment with the pod template it already runs,
+// so the rest of the spec and the replica count stay managed while a rollout is pending.
+func (r *WebAppReconciler) applyStableDeployment(ctx context.Context, webapp *appv1alpha1.WebApp,
+	desired, existing *appsv1.Deployment, replicas *int32) error {
+	stable := desired.DeepCopy()
+	stable.Annotations = map[string]string{
+		appliedHashAnnotation:  existing.Annotations[appliedHashAnnotation],
+		templateHashAnnotation: existing.Annotations[templateHashAnnotation],
+	}
+	stable.Spec.Replicas = replicas
+	stable.Spec.Template = *existing.Spec.Template.DeepCopy()
+	return r.apply(ctx, webapp, stable, "deployment")
+}
+
+// deleteRolloutObjects deletes the canary and preview Deployments and the preview Service.
+func (r *WebAppReconciler) deleteRolloutObjects(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	objects := []struct {
+		obj  client.Object
+		kind string
+	}{
+		{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryName(webapp.Name)}}, "canary deployment"},
+		{&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: previewName(webapp.Name)}}, "preview deployment"},
+		{&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: previewName(webapp.Name)}}, "preview service"},
+	}
+	for _, o := range objects {
+		if err := r.deleteOwned(ctx, webapp, o.obj, o.kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rolloutDeployment returns a copy of the desired Deployment under another name, selecting
+// pods by the given labels. Spread constraints that select the main Deployment's pods
+// select its own instead. It carries only the template hash annotation, since drift
+// handling applies to the main Deployment alone.
+func rolloutDeployment(desired *appsv1.Deployment, name string, labels map[string]string, replicas int32) *appsv1.Deployment {
+	dep := desired.DeepCopy()
+	dep.Name = name
+	dep.Annotations = map[string]string{templateHashAnnotation: desired.Annotations[templateHashAnnotation]}
+	dep.Spec.Replicas = ptr.To(replicas)
+	dep.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+	mainPods := mainPodSelectorForWebApp(desired.Name)
+	for i, c := range dep.Spec.Template.Spec.TopologySpreadConstraints {
+		if equality.Semantic.DeepEqual(c.LabelSelector, mainPods) {
+			dep.Spec.Template.Spec.TopologySpreadConstraints[i].LabelSelector = &metav1.LabelSelector{
+				MatchLabels: maps.Clone(labels),
+			}
+		}
+	}
+	dep.Spec.Template.Labels = maps.Clone(labels)
+	return dep
+}
+
+// canaryStepReplicas splits the replica count of a Canary step. The canary gets the
+// weight's share of the total, rounded up so every step runs at least one canary pod.
+// The main Deployment keeps the rest, but never fewer than one pod, so the previous
+// version keeps serving at small replica counts and at a weight of 100.
+func canaryStepReplicas(total, weight int32) (canary, stable int32) {
+	canary = max((total*weight+99)/100, 1)
+	return canary, max(total-canary, min(total, 1))
+}
+
+// shortRevision shortens a template hash for messages and events.
+func shortRevision(revision string) string {
+	return revision[:min(len(revision), 10)]
+}
+
+// serviceSelectorForWebApp returns the selector of the main Service. While a promoted
+// BlueGreen rollout rolls out the main Deployment, the Service selects the preview pods.
+func serviceSelectorForWebApp(webapp *appv1alpha1.WebApp) map[string]string {
+	st := webapp.Status.Rollout
+	if st != nil && st.Strategy == appv1alpha1.RolloutStrategyBlueGreen && st.Phase == appv1alpha1.RolloutPhasePromoting {
+		return previewLabelsForWebApp(webapp.Name)
+	}
+	return labelsForWebApp(webapp.Name)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appv1alpha1 "github.com/54b3r/platform-operator-blueprint/api/v1alpha1"
+)
+
+// suspendedReplicasAnnotation is set on a Deployment scaled to zero by
+// Spec.Suspend.ScaleToZero to the replica count it had before, which is restored on resume.
+const suspendedReplicasAnnotation = "app.54b3r.io/suspended-replicas"
+
+// reconcileSuspended handles a WebApp with Spec.Suspend set. Child resources are left as
+// they are, except that ScaleToZero scales the Deployment to zero replicas, and clearing
+// ScaleToZero while still suspended scales it back.
+func (r *WebAppReconciler) reconcileSuspended(ctx context.Context, webapp *appv1alpha1.WebApp) error {
+	if !meta.IsStatusConditionTrue(webapp.Status.Conditions, appv1alpha1.TypeSuspended) {
+		logf.FromContext(ctx).Info("suspending reconciliation", "name", webapp.Name)
+		r.recordEvent(webapp, corev1.EventTypeNormal, "Suspended", "suspended reconciliation of child resources")
+	}
+	scaleToZero := webapp.Spec.Suspend.ScaleToZero
+	reason, message := "Suspended", "child resources are left unchanged until spec.suspend is removed"
+	if scaleToZero {
+		reason, message = "ScaledToZero",
+			"the deployment is scaled to zero replicas and child resources are left unchanged until spec.suspend is removed"
+	}
+	setCondition(webapp, appv1alpha1.TypeSuspended, metav1.ConditionTrue, reason, message)
+
+	dep := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: webapp.Name, Namespace: webapp.Namespace}, dep)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting deployment: %w", err)
+	}
+
+	previous, scaledDown := dep.Annotations[suspendedReplicasAnnotation]
+	switch {
+	case scaleToZero && !scaledDown && ptr.Deref(dep.Spec.Replicas, 1) > 0:
+		replicas := ptr.Deref(dep.Spec.Replicas, 1)
+		if err := r.scaleSuspendedDeployment(ctx, webapp, dep, 0, ptr.To(strconv.Itoa(int(replicas)))); err != nil {
+			return err
+		}
+		r.recordEvent(webapp, corev1.EventTypeNormal, "ScaledToZero",
+			"scaled deployment %s to zero replicas from %d", dep.Name, replicas)
+	case !scaleToZero && scaledDown:
+		replicas, ok := parseSuspendedReplicas(previous)
+		if !ok {
+			replicas = ptr.Deref(dep.Spec.Replicas, 1)
+		}
+		if err := r.scaleSuspendedDeployment(ctx, webapp, dep, replicas, nil); err != nil {
+			return err
+		}
+		r.recordEvent(webapp, corev1.EventTypeNormal, "ScaledUp",
+			"restored deployment %s to %d replica(s)", dep.Name, replicas)
+	}
+	return nil
+}
+
+// scaleSuspendedDeployment sets the replica count of a Deployment without touching any
+// other field: it re-applies the fields the operator last applied, with the new count.
+// previous is stored in suspendedReplicasAnnotation, or the annotation is dropped when
+// nil. The applied hash is dropped too, so the scaling is not mistaken for drift and the
+// whole spec is applied again on resume.
+func (r *WebAppReconciler) scaleSuspendedDeployment(ctx context.Context, webapp *appv1alpha1.WebApp,
+	dep *appsv1.Deployment, replicas int32, previous *string) error {
+	ac, err := appsv1ac.ExtractDeployment(dep, fieldManager)
+	if err != nil {
+		return fmt.Errorf("extracting applied deployment fields: %w", err)
+	}
+	if ac.Spec == nil {
+		ac.Spec = appsv1ac.DeploymentSpec()
+	}
+	ac.Spec.WithReplicas(replicas)
+	delete(ac.Annotations, appliedHashAnnotation)
+	delete(ac.Annotations, suspendedReplicasAnnotation)
+	if previous != nil {
+		ac.WithAnnotations(map[string]string{suspendedReplicasAnnotation: *previous})
+	}
+
+	data, err := json.Marshal(ac)
+	if err != nil {
+		return fmt.Errorf("encoding deployment scale: %w", err)
+	}
+	// Force ownership of the replica count, which the autoscaler may share.
+	if err := r.Patch(ctx, dep, client.RawPatch(types.ApplyPatchType, data),
+		client.FieldOwner(fieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("scaling deployment to %d replica(s): %w", replicas, err)
+	}
+	recordChildOperation(webapp, "deployment", "update")
+	return nil
+}
+
+// parseSuspendedReplicas parses the value of suspendedReplicasAnnotation.
+func parseSuspendedReplicas(value string) (int32, bool) {
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas < 0 {
+		return 0, false
+	}
+	return int32(replicas), true
+}
+
+// resumeWebApp removes the Suspended condition once Spec.Suspend is cleared. The replica
+// count a suspended Deployment had is restored by reconcileDeployment.
+func (r *WebAppReconciler) resumeWebApp(ctx context.Context, webapp *appv1alpha1.WebApp) {
+	if meta.FindStatusCondition(webapp.Status.Conditions, appv1alpha1.TypeSuspended) == nil {
+		return
+	}
+	logf.FromContext(ctx).Info("resuming reconciliation", "name", webapp.Name)
+	r.recordEvent(webapp, corev1.EventTypeNormal, "Resumed", "resumed reconciliation of child resources")
+	meta.RemoveStatusCondition(&webapp.Status.Conditions, appv1alpha1.TypeSuspended)
+}
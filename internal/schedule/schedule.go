@@ -0,0 +1,90 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule parses and evaluates the cron expressions of WebApp replica schedules.
+// The admission webhook and the controller share it, so an expression is accepted exactly
+// when it can be evaluated.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parser parses the five-field cron expressions and macros of replica schedules.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// lookback is how far back Last searches for the time a schedule fired, widening the
+// window step by step so that frequent schedules are found quickly. The last step, four
+// years and two days, covers every expression that fires at all, including those limited
+// to February 29.
+var lookback = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	32 * 24 * time.Hour,
+	367 * 24 * time.Hour,
+	(4*365 + 2) * 24 * time.Hour,
+}
+
+// Schedule is a parsed cron expression evaluated in a time zone.
+type Schedule struct {
+	spec *cron.SpecSchedule
+}
+
+// Parse parses the cron expression of a replica schedule: five fields (minute, hour, day
+// of month, month and day of week) or a macro such as @daily, evaluated in loc. The time
+// zone comes from the schedule's timeZone field, so a TZ= or CRON_TZ= prefix is rejected,
+// and @every is rejected because it fires relative to when it was parsed.
+func Parse(expr string, loc *time.Location) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, fmt.Errorf("must not name a time zone; set timeZone instead")
+	}
+	parsed, err := parser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := parsed.(*cron.SpecSchedule)
+	if !ok {
+		return nil, fmt.Errorf("@every is not supported; use a five-field expression")
+	}
+	spec.Location = loc
+	return &Schedule{spec: spec}, nil
+}
+
+// Next returns the first time after t at which the schedule fires, or the zero time when
+// it never does.
+func (s *Schedule) Next(t time.Time) time.Time {
+	return s.spec.Next(t)
+}
+
+// Last returns the last time at or before t at which the schedule fired. It returns false
+// when the schedule did not fire within the longest lookback window.
+func (s *Schedule) Last(t time.Time) (time.Time, bool) {
+	for _, window := range lookback {
+		var last time.Time
+		for next := s.Next(t.Add(-window)); !next.IsZero() && !next.After(t); next = s.Next(next) {
+			last = next
+		}
+		if !last.IsZero() {
+			return last, true
+		}
+	}
+	return time.Time{}, false
+}